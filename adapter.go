@@ -1,6 +1,10 @@
 package errors
 
-import "sync"
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
 
 // Adapter is an interface implemented by types that support adapting errors to
 // be introspected by functions of the erorrs package.
@@ -23,18 +27,72 @@ func (f AdapterFunc) Adapt(err error) (error, bool) { return f(err) }
 // rely on the fact that functions like Wrap, WithMessage, WithStack... will
 // automatically adapt the errors that they receive.
 func Adapt(err error) error {
-	switch err.(type) {
-	case *baseError, *multiError, *errorWithMessage, *errorWithStack, *errorWithTypes, *errorWithTags, *errorTODO, *errorValue:
+	if isInternalError(err) {
 		// fast path: when the error is already one of the internal error types
 		// of this package there is no need to go over the list of adapters.
 		return err
 	}
-	return adapters.adapt(err, 1)
+	if e, ok := adapters.adapt(err, 1); ok {
+		err = e
+	} else {
+		err, _ = fallbackAdapters.adapt(err, 1)
+	}
+
+	if hook, _ := adaptHook.Load().(func(error) error); hook != nil && err != nil {
+		adapted := &errorAdapted{cause: err}
+		if e := hook(adapted); e != nil && e != adapted {
+			return e
+		}
+	}
+
+	return err
+}
+
+// adaptHook holds the func(error) error set by SetAdaptHook.
+var adaptHook atomic.Value
+
+// SetAdaptHook installs a function that Adapt calls on every error it adapts,
+// after the registered adapters were applied, which gives programs a single
+// place to post-process errors coming from other packages. For example, this
+// hook tags all errors with the name of the service:
+//
+//	errors.SetAdaptHook(func(err error) error {
+//		return errors.WithTags(err, errors.T("service", "api"))
+//	})
+//
+// The error passed to the hook wraps the adapted error in a type of this
+// package, so the hook can use functions like WithTags without invoking the
+// hook recursively. The hook is not called on errors that were created by this
+// package, which are returned unchanged by Adapt.
+//
+// If the hook returns nil, the error is returned unchanged by Adapt, so the
+// errors wrapped by functions like Wrap or WithTags never end up with a nil
+// cause. Passing nil removes the hook, which is the default.
+func SetAdaptHook(hook func(error) error) {
+	adaptHook.Store(hook)
+}
+
+// isInternalError returns true if err is one of the error types of this
+// package.
+func isInternalError(err error) bool {
+	switch err.(type) {
+	case *baseError, *multiError, *errorWithMessage, *errorWithStack, *errorWithTypes, *errorWithTags, *errorWithoutTags, *errorWithoutTypes, *errorWithoutStack, *errorWithRetryAfter, *errorWithContext, *errorAdapted, *errorTODO, *errorValue:
+		return true
+	default:
+		return false
+	}
 }
 
 // Register registers a new error adapter.
 func Register(a Adapter) { adapters.register(a) }
 
+// RegisterFallback registers an error adapter which is only tried on errors
+// that none of the adapters installed with Register recognized. It is intended
+// for adapters matching errors by the methods that they implement rather than
+// by their concrete type, so they do not take over errors that have a dedicated
+// adapter, regardless of the order in which the adapters were registered.
+func RegisterFallback(a Adapter) { fallbackAdapters.register(a) }
+
 type adapterStore struct {
 	mutex    sync.RWMutex
 	adapters []Adapter
@@ -48,21 +106,42 @@ func (store *adapterStore) register(a Adapter) {
 	}
 }
 
-func (store *adapterStore) adapt(err error, depth int) error {
+func (store *adapterStore) adapt(err error, depth int) (error, bool) {
 	if err != nil {
 		store.mutex.RLock()
 		defer store.mutex.RUnlock()
 
 		for _, a := range store.adapters {
 			if e, ok := a.Adapt(err); ok {
-				err = e
-				break
+				return e, true
 			}
 		}
 	}
-	return err
+	return err, false
 }
 
 // adapters is the global store of error adapters that the program has setup by
 // calling Register.
 var adapters adapterStore
+
+// fallbackAdapters is the global store of error adapters that the program has
+// setup by calling RegisterFallback.
+var fallbackAdapters adapterStore
+
+// errorAdapted wraps errors passed to the hook installed by SetAdaptHook.
+type errorAdapted struct {
+	typesCache
+	cause error
+}
+
+func (e *errorAdapted) Cause() error {
+	return e.cause
+}
+
+func (e *errorAdapted) Error() string {
+	return e.cause.Error()
+}
+
+func (e *errorAdapted) Format(s fmt.State, v rune) {
+	format(s, v, e)
+}
@@ -1,6 +1,10 @@
 package errors
 
-import "testing"
+import (
+	"fmt"
+	"io"
+	"testing"
+)
 
 func TestAdapter(t *testing.T) {
 	adaptable := &adaptableError{}
@@ -42,3 +46,102 @@ type adapterError struct{ cause error }
 
 func (e *adapterError) Error() string { return "adapted: " + e.cause.Error() }
 func (e *adapterError) Cause() error  { return e.cause }
+
+func TestAdaptHook(t *testing.T) {
+	SetAdaptHook(func(err error) error {
+		return WithTags(err, T("service", "test"))
+	})
+	defer SetAdaptHook(nil)
+
+	err := Adapt(io.ErrClosedPipe)
+
+	if tags := TagsMap(err); len(tags["service"]) != 1 || tags["service"][0] != "test" {
+		t.Error("the hook did not tag the adapted error:", tags)
+	}
+
+	if cause := Cause(err); cause != io.ErrClosedPipe {
+		t.Error("wrong cause exposed on the error returned by the hook:", cause)
+	}
+
+	if s := fmt.Sprint(err); s != `io: read/write on closed pipe [service:"test"]` {
+		t.Error("bad error format:", s)
+	}
+
+	if err := WithMessage(io.ErrShortWrite, "oops"); len(TagsMap(err)["service"]) != 1 {
+		t.Error("the hook was not called on an error wrapped by the package:", Tags(err))
+	}
+
+	internal := New("internal")
+
+	if err := Adapt(internal); err != internal {
+		t.Error("the hook must not be called on errors of the package:", err)
+	}
+
+	if err := Adapt(nil); err != nil {
+		t.Error("adapting a nil error did not return nil:", err)
+	}
+}
+
+func TestAdaptHookIdentity(t *testing.T) {
+	SetAdaptHook(func(err error) error { return err })
+	defer SetAdaptHook(nil)
+
+	if err := Adapt(io.ErrClosedPipe); err != io.ErrClosedPipe {
+		t.Error("the adapted error must be returned when the hook does not change it:", err)
+	}
+}
+
+func TestAdaptHookNil(t *testing.T) {
+	SetAdaptHook(func(err error) error { return nil })
+	defer SetAdaptHook(nil)
+
+	if err := Adapt(io.ErrClosedPipe); err != io.ErrClosedPipe {
+		t.Error("the adapted error must be returned when the hook returns nil:", err)
+	}
+
+	err := Wrap(io.ErrClosedPipe, "oops")
+
+	if s := err.Error(); s != "oops: io: read/write on closed pipe" {
+		t.Error("bad error message:", s)
+	}
+
+	if cause := Cause(err); cause != io.ErrClosedPipe {
+		t.Error("wrong cause exposed on the wrapped error:", cause)
+	}
+}
+
+func TestRegisterFallback(t *testing.T) {
+	both := &fallbackError{}
+	only := &fallbackError{}
+
+	RegisterFallback(AdapterFunc(func(err error) (error, bool) {
+		if e, ok := err.(*fallbackError); ok {
+			return &adapterError{cause: e}, true
+		}
+		return err, false
+	}))
+
+	Register(AdapterFunc(func(err error) (error, bool) {
+		if err == both {
+			return &adaptedFallbackError{cause: err}, true
+		}
+		return err, false
+	}))
+
+	if _, ok := Adapt(both).(*adaptedFallbackError); !ok {
+		t.Error("the fallback adapter took over an error recognized by another adapter")
+	}
+
+	if _, ok := Adapt(only).(*adapterError); !ok {
+		t.Error("the fallback adapter was not applied")
+	}
+}
+
+type fallbackError struct{ _ int }
+
+func (*fallbackError) Error() string { return "fallback" }
+
+type adaptedFallbackError struct{ cause error }
+
+func (e *adaptedFallbackError) Error() string { return e.cause.Error() }
+func (e *adaptedFallbackError) Cause() error  { return e.cause }
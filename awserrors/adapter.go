@@ -2,12 +2,21 @@ package awserrors
 
 import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/smithy-go"
 	errors "github.com/segmentio/errors-go"
 )
 
-// Adapt checks the type of err and if it matches one of the error types or one
-// of the error values of the standard net package, adapts it to make error
-// types discoverable using the errors.Is function.
+// Adapt checks the type of err and if it matches one of the error types of the
+// AWS SDK, adapts it to make error types discoverable using the errors.Is
+// function.
+//
+// Both the errors of the original SDK (awserr.Error and awserr.BatchError) and
+// the errors of aws-sdk-go-v2 (smithy.APIError, smithy.OperationError and HTTP
+// response errors) are recognized.
+//
+// The delay of the Retry-After header of responses is only exposed on errors of
+// aws-sdk-go-v2, the errors of the original SDK do not carry the response that
+// they were constructed from.
 //
 // This function is automatically installed as a global adapter when importing
 // the neterrors package, a program likely should use errors.Adapt instead of
@@ -20,6 +29,15 @@ func Adapt(err error) (error, bool) {
 	case awserr.BatchError:
 		return &awsBatchError{e}, true
 
+	case smithy.APIError:
+		return &apiError{e}, true
+
+	case *smithy.OperationError:
+		return &operationError{e}, true
+
+	case responseError:
+		return &httpResponseError{e}, true
+
 	default:
 		return err, false
 	}
@@ -81,6 +99,18 @@ func (e *awsError) RequestID() string {
 	return ""
 }
 
+// Common error types
+
+func (e *awsError) Throttled() bool { return throttled(e.Code(), e.StatusCode()) }
+
+func (e *awsError) Timeout() bool { return timeout(e.Code(), e.StatusCode()) }
+
+func (e *awsError) PermissionDenied() bool { return permissionDenied(e.Code(), e.StatusCode()) }
+
+func (e *awsError) NotFound() bool { return notFound(e.Code(), e.StatusCode()) }
+
+func (e *awsError) Temporary() bool { return temporary(e.Code(), e.StatusCode()) }
+
 type awsBatchError struct {
 	cause awserr.BatchError
 }
@@ -100,3 +130,50 @@ func (e *awsBatchError) Message() string {
 func (e *awsBatchError) Causes() []error {
 	return adaptErrors(e.cause.OrigErrs())
 }
+
+// The functions below classify errors based on their AWS error codes, or the
+// HTTP status code of the response when the error code is not recognized.
+
+func throttled(code string, status int) bool {
+	switch code {
+	case "Throttling",
+		"ThrottlingException",
+		"ThrottledException",
+		"RequestThrottled",
+		"RequestThrottledException",
+		"TooManyRequestsException",
+		"RequestLimitExceeded",
+		"ProvisionedThroughputExceededException",
+		"SlowDown":
+		return true
+	}
+	return status == 429
+}
+
+func timeout(code string, status int) bool {
+	switch code {
+	case "RequestTimeout", "RequestTimeoutException":
+		return true
+	}
+	return status == 408
+}
+
+func permissionDenied(code string, status int) bool {
+	switch code {
+	case "AccessDenied", "AccessDeniedException":
+		return true
+	}
+	return status == 403
+}
+
+func notFound(code string, status int) bool {
+	switch code {
+	case "ResourceNotFoundException", "NotFound", "NoSuchKey", "NoSuchBucket":
+		return true
+	}
+	return status == 404
+}
+
+func temporary(code string, status int) bool {
+	return throttled(code, status) || timeout(code, status) || status >= 500
+}
@@ -2,6 +2,7 @@ package awserrors
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -210,6 +211,88 @@ func testAdaptRequestError(t *testing.T) {
 	}
 }
 
+func TestAdaptTypes(t *testing.T) {
+	tests := []struct {
+		err   error
+		types []string
+	}{
+		{
+			err:   &singleError{code: "ValidationException"},
+			types: nil,
+		},
+		{
+			err:   &singleError{code: "Throttling"},
+			types: []string{"Temporary", "Throttled"},
+		},
+		{
+			err:   &singleError{code: "ThrottlingException"},
+			types: []string{"Temporary", "Throttled"},
+		},
+		{
+			err:   &singleError{code: "RequestLimitExceeded"},
+			types: []string{"Temporary", "Throttled"},
+		},
+		{
+			err:   &singleError{code: "ProvisionedThroughputExceededException"},
+			types: []string{"Temporary", "Throttled"},
+		},
+		{
+			err:   &singleError{code: "RequestTimeout"},
+			types: []string{"Temporary", "Timeout"},
+		},
+		{
+			err:   &singleError{code: "AccessDenied"},
+			types: []string{"PermissionDenied"},
+		},
+		{
+			err:   &singleError{code: "ResourceNotFoundException"},
+			types: []string{"NotFound"},
+		},
+		{
+			err:   &requestError{code: "SomethingElse", status: 429},
+			types: []string{"Temporary", "Throttled"},
+		},
+		{
+			err:   &requestError{code: "SomethingElse", status: 403},
+			types: []string{"PermissionDenied"},
+		},
+		{
+			err:   &requestError{code: "SomethingElse", status: 404},
+			types: []string{"NotFound"},
+		},
+		{
+			err:   &requestError{code: "InternalFailure", status: 500},
+			types: []string{"Temporary"},
+		},
+		{
+			err:   &requestError{code: "ServiceUnavailable", status: 503},
+			types: []string{"Temporary"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s/%d", code(test.err), statusCode(test.err)), func(t *testing.T) {
+			err, ok := Adapt(test.err)
+
+			if !ok {
+				t.Fatal("the error was not recognized")
+			}
+
+			for _, typ := range test.types {
+				if !errors.Is(typ, err) {
+					t.Errorf("the error was expected to be a %q error", typ)
+				}
+			}
+
+			if types := errors.Types(err); !reflect.DeepEqual(types, test.types) {
+				t.Error("types mismatch")
+				t.Log("expected:", test.types)
+				t.Log("found:   ", types)
+			}
+		})
+	}
+}
+
 func code(err error) string {
 	e, ok := err.(interface {
 		Code() string
@@ -303,3 +386,11 @@ func (e *requestError) Message() string   { return e.msg }
 func (e *requestError) OrigErr() error    { return nil }
 func (e *requestError) StatusCode() int   { return e.status }
 func (e *requestError) RequestID() string { return e.id }
+
+func TestRequestFailureRetryAfter(t *testing.T) {
+	err := errors.Adapt(&requestError{code: "Throttling", msg: "Rate exceeded", id: "1234", status: 429})
+
+	if d, ok := errors.RetryAfter(err); ok || d != 0 {
+		t.Error("unexpected retry-after duration on an error of the original SDK:", d)
+	}
+}
@@ -0,0 +1,7 @@
+package awserrors
+
+import errors "github.com/segmentio/errors-go"
+
+func init() {
+	errors.Register(errors.AdapterFunc(Adapt))
+}
@@ -0,0 +1,129 @@
+package awserrors
+
+import (
+	"time"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	errors "github.com/segmentio/errors-go"
+)
+
+// responseError is the interface implemented by the HTTP response errors of
+// aws-sdk-go-v2 (both *http.ResponseError of smithy-go and the one of the
+// aws/transport/http package which embeds it).
+type responseError interface {
+	error
+	HTTPStatusCode() int
+	Unwrap() error
+}
+
+type apiError struct {
+	cause smithy.APIError
+}
+
+func (e *apiError) Error() string {
+	return e.cause.Error()
+}
+
+func (e *apiError) Code() string {
+	return e.cause.ErrorCode()
+}
+
+func (e *apiError) Message() string {
+	return e.cause.ErrorMessage()
+}
+
+func (e *apiError) Cause() error {
+	if u, ok := e.cause.(interface{ Unwrap() error }); ok {
+		return errors.Adapt(u.Unwrap())
+	}
+	return nil
+}
+
+func (e *apiError) Throttled() bool { return throttled(e.Code(), 0) }
+
+func (e *apiError) Timeout() bool { return timeout(e.Code(), 0) }
+
+func (e *apiError) PermissionDenied() bool { return permissionDenied(e.Code(), 0) }
+
+func (e *apiError) NotFound() bool { return notFound(e.Code(), 0) }
+
+func (e *apiError) Temporary() bool {
+	return temporary(e.Code(), 0) || e.cause.ErrorFault() == smithy.FaultServer
+}
+
+type operationError struct {
+	cause *smithy.OperationError
+}
+
+func (e *operationError) Error() string {
+	return e.cause.Error()
+}
+
+func (e *operationError) Message() string {
+	return "operation error " + e.cause.ServiceID + ": " + e.cause.OperationName
+}
+
+func (e *operationError) Cause() error {
+	return errors.Adapt(e.cause.Err)
+}
+
+type httpResponseError struct {
+	cause responseError
+}
+
+func (e *httpResponseError) Error() string {
+	return e.cause.Error()
+}
+
+func (e *httpResponseError) Cause() error {
+	return errors.Adapt(e.cause.Unwrap())
+}
+
+func (e *httpResponseError) StatusCode() int {
+	return e.cause.HTTPStatusCode()
+}
+
+func (e *httpResponseError) RequestID() string {
+	if r, ok := e.cause.(interface{ ServiceRequestID() string }); ok {
+		return r.ServiceRequestID()
+	}
+	return ""
+}
+
+// RetryAfter returns the delay suggested by the Retry-After header of the
+// response, which services may set on throttling or unavailability errors.
+// The delay can be retrieved with errors.RetryAfter.
+func (e *httpResponseError) RetryAfter() time.Duration {
+	r, ok := e.cause.(interface{ HTTPResponse() *smithyhttp.Response })
+	if !ok {
+		return 0
+	}
+	if res := r.HTTPResponse(); res != nil && res.Response != nil {
+		return errors.ParseRetryAfter(res.Header.Get("Retry-After"))
+	}
+	return 0
+}
+
+// The types of HTTP response errors account for the code of the API error that
+// they wrap, otherwise their status-based classification would hide the more
+// specific types of the API error from errors.Is.
+
+func (e *httpResponseError) Throttled() bool { return throttled(e.code(), e.StatusCode()) }
+
+func (e *httpResponseError) Timeout() bool { return timeout(e.code(), e.StatusCode()) }
+
+func (e *httpResponseError) PermissionDenied() bool {
+	return permissionDenied(e.code(), e.StatusCode())
+}
+
+func (e *httpResponseError) NotFound() bool { return notFound(e.code(), e.StatusCode()) }
+
+func (e *httpResponseError) Temporary() bool { return temporary(e.code(), e.StatusCode()) }
+
+func (e *httpResponseError) code() string {
+	if a, ok := e.cause.Unwrap().(smithy.APIError); ok {
+		return a.ErrorCode()
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package awserrors
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	errors "github.com/segmentio/errors-go"
+)
+
+func TestAdaptSmithy(t *testing.T) {
+	t.Run("adapting API errors exposes the correct code, message, and cause", func(t *testing.T) {
+		e0 := errors.New("base error")
+		e1 := &fakeAPIError{code: "ResourceNotFoundException", msg: "no such table", fault: smithy.FaultClient, cause: e0}
+		e2, ok := Adapt(e1)
+
+		if !ok {
+			t.Fatal("adapting API errors must return true to indicate that the error was recognized")
+		}
+
+		if s1, s2 := e1.Error(), e2.Error(); s1 != s2 {
+			t.Errorf("bad error string: %q != %q", s2, s1)
+		}
+
+		if c := code(e2); c != "ResourceNotFoundException" {
+			t.Error("bad error code:", c)
+		}
+
+		if m := message(e2); m != "no such table" {
+			t.Error("bad error message:", m)
+		}
+
+		if cause := errors.Cause(e2); cause != e0 {
+			t.Error("bad error cause:", cause)
+		}
+
+		if !errors.Is("NotFound", e2) || errors.Is("Temporary", e2) {
+			t.Error("bad error types:", errors.Types(e2))
+		}
+	})
+
+	t.Run("server faults are temporary", func(t *testing.T) {
+		err, _ := Adapt(&smithy.GenericAPIError{Code: "InternalError", Fault: smithy.FaultServer})
+
+		if !errors.Is("Temporary", err) {
+			t.Error("bad error types:", errors.Types(err))
+		}
+	})
+
+	t.Run("adapting operation errors exposes the status code and the types of the API error", func(t *testing.T) {
+		e0 := &smithy.GenericAPIError{Code: "ThrottlingException", Message: "rate exceeded"}
+		e1 := &smithy.OperationError{
+			ServiceID:     "DynamoDB",
+			OperationName: "GetItem",
+			Err: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: 400}},
+				Err:      e0,
+			},
+		}
+		e2 := errors.Adapt(e1)
+
+		if msgs, _, _, _, _ := errors.Inspect(e2); len(msgs) != 2 || msgs[0] != "operation error DynamoDB: GetItem" || msgs[1] != "rate exceeded" {
+			t.Error("bad error messages:", msgs)
+		}
+
+		if !errors.Is("Throttled", e2) || !errors.Is("Temporary", e2) {
+			t.Error("bad error types:", errors.Types(e2))
+		}
+
+		var status int
+		errors.Walk(e2, func(err error) bool {
+			if sc := statusCode(err); sc != 0 {
+				status = sc
+			}
+			return true
+		})
+
+		if status != 400 {
+			t.Error("bad status code:", status)
+		}
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC)
+	errors.SetClock(func() time.Time { return now })
+	defer errors.SetClock(nil)
+
+	tests := []struct {
+		scenario   string
+		header     http.Header
+		retryAfter time.Duration
+	}{
+		{"no header", http.Header{}, 0},
+		{"seconds", http.Header{"Retry-After": {"3"}}, 3 * time.Second},
+		{"http date", http.Header{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}}, time.Minute},
+		{"invalid", http.Header{"Retry-After": {"soon"}}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			err := errors.Adapt(&smithy.OperationError{
+				ServiceID:     "SQS",
+				OperationName: "SendMessage",
+				Err: &smithyhttp.ResponseError{
+					Response: &smithyhttp.Response{Response: &http.Response{StatusCode: 503, Header: test.header}},
+					Err:      &smithy.GenericAPIError{Code: "ServiceUnavailable", Fault: smithy.FaultServer},
+				},
+			})
+
+			d, ok := errors.RetryAfter(err)
+
+			if d != test.retryAfter || ok != (test.retryAfter != 0) {
+				t.Error("bad retry-after duration:", d, ok)
+			}
+		})
+	}
+}
+
+type fakeAPIError struct {
+	code  string
+	msg   string
+	fault smithy.ErrorFault
+	cause error
+}
+
+func (e *fakeAPIError) Error() string                 { return e.code + ": " + e.msg }
+func (e *fakeAPIError) ErrorCode() string             { return e.code }
+func (e *fakeAPIError) ErrorMessage() string          { return e.msg }
+func (e *fakeAPIError) ErrorFault() smithy.ErrorFault { return e.fault }
+func (e *fakeAPIError) Unwrap() error                 { return e.cause }
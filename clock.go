@@ -0,0 +1,29 @@
+package errors
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// clock holds the func() time.Time set by SetClock.
+var clock atomic.Value
+
+// SetClock installs the function used by the package and its sub-packages to
+// read the current time, which is mostly useful to make tests of time-dependent
+// features deterministic:
+//
+//	errors.SetClock(func() time.Time { return now })
+//	defer errors.SetClock(nil)
+//
+// Passing nil restores the default clock, which is time.Now.
+func SetClock(now func() time.Time) {
+	clock.Store(now)
+}
+
+// Now returns the current time according to the clock installed by SetClock.
+func Now() time.Time {
+	if now, _ := clock.Load().(func() time.Time); now != nil {
+		return now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,23 @@
+package errors
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetClock(t *testing.T) {
+	now := time.Date(2020, time.January, 2, 3, 4, 5, 6, time.UTC)
+
+	SetClock(func() time.Time { return now })
+	defer SetClock(nil)
+
+	if t1 := Now(); !t1.Equal(now) {
+		t.Errorf("bad time: %s != %s", t1, now)
+	}
+
+	SetClock(nil)
+
+	if t1 := Now(); t1.Equal(now) {
+		t.Error("the default clock was not restored")
+	}
+}
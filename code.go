@@ -0,0 +1,62 @@
+package errors
+
+// errorCodes is the table used by ErrorCode to map types to error codes, the
+// first entry matching the type of an error determines its code.
+var errorCodes = []struct {
+	typ  string
+	code string
+}{
+	{"NotFound", "not_found"},
+	{"AlreadyExists", "already_exists"},
+	{"Conflict", "conflict"},
+	{"Validation", "invalid_argument"},
+	{"InvalidArgument", "invalid_argument"},
+	{"BadRequest", "invalid_argument"},
+	{"Unauthenticated", "unauthenticated"},
+	{"Unauthorized", "unauthenticated"},
+	{"PermissionDenied", "permission_denied"},
+	{"Forbidden", "permission_denied"},
+	{"NotImplemented", "not_implemented"},
+	{"Canceled", "canceled"},
+	{"Throttled", "rate_limited"},
+	{"Timeout", "timeout"},
+	{"Unavailable", "unavailable"},
+	{"ServiceUnavailable", "unavailable"},
+	{"Temporary", "unavailable"},
+}
+
+// ErrorCode returns a stable, machine-readable code describing err, which is
+// intended to be exposed to clients of APIs instead of the names of the types
+// of the error. The code is derived from the first type of err in this table:
+//
+//	NotFound            not_found
+//	AlreadyExists       already_exists
+//	Conflict            conflict
+//	Validation          invalid_argument
+//	InvalidArgument     invalid_argument
+//	BadRequest          invalid_argument
+//	Unauthenticated     unauthenticated
+//	Unauthorized        unauthenticated
+//	PermissionDenied    permission_denied
+//	Forbidden           permission_denied
+//	NotImplemented      not_implemented
+//	Canceled            canceled
+//	Throttled           rate_limited
+//	Timeout             timeout
+//	Unavailable         unavailable
+//	ServiceUnavailable  unavailable
+//	Temporary           unavailable
+//
+// Errors that have none of those types get the "internal" code. If err is nil,
+// the function returns an empty string.
+func ErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, c := range errorCodes {
+		if Is(c.typ, err) {
+			return c.code
+		}
+	}
+	return "internal"
+}
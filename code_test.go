@@ -0,0 +1,28 @@
+package errors
+
+import "testing"
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{nil, ""},
+		{New("hello"), "internal"},
+		{WithTypes(New("hello"), "NotFound", "Temporary"), "not_found"},
+		{WithTypes(New("hello"), "InvalidArgument", "Validation"), "invalid_argument"},
+		{WithTypes(New("hello"), "TooManyRequests", "Throttled", "Temporary"), "rate_limited"},
+		{WithTypes(New("hello"), "Forbidden"), "permission_denied"},
+		{Wrap(&timeout{}, "hello"), "timeout"},
+		{WithTypes(New("hello"), "ServiceUnavailable", "Temporary"), "unavailable"},
+		{NotImplemented("hello"), "not_implemented"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.code, func(t *testing.T) {
+			if code := ErrorCode(test.err); code != test.code {
+				t.Errorf("bad error code: %q != %q", code, test.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package errors
+
+import (
+	"context"
+	"sync"
+)
+
+// Collector accumulates errors reported by concurrent goroutines and combines
+// them into a single error. The zero-value is ready to use.
+//
+//	c := errors.Collector{}
+//	wg := sync.WaitGroup{}
+//
+//	for _, t := range tasks {
+//		wg.Add(1)
+//		go func(t task) { c.Collect(t()); wg.Done() }(t)
+//	}
+//
+//	wg.Wait()
+//	err := c.Err()
+//
+// By default the collector accumulates all errors. Setting FailFast configures
+// the collector to only retain the first error, and call Cancel when it is
+// collected so the remaining work can be interrupted:
+//
+//	ctx, cancel := context.WithCancel(ctx)
+//	c := errors.Collector{FailFast: true, Cancel: cancel}
+//
+// Collector values must not be copied after first use.
+type Collector struct {
+	// When true, only the first error is retained and subsequent errors are
+	// discarded.
+	FailFast bool
+
+	// Cancel is called when the first error is collected, if FailFast is set.
+	Cancel context.CancelFunc
+
+	mutex  sync.Mutex
+	errors []error
+}
+
+// Collect records err in the collector, nil errors are ignored. The method is
+// safe to call from multiple goroutines.
+//
+// All errors passed to the method are adapted.
+func (c *Collector) Collect(err error) {
+	if err == nil {
+		return
+	}
+
+	err = Adapt(err)
+	c.mutex.Lock()
+
+	if !c.FailFast {
+		c.errors = append(c.errors, err)
+		c.mutex.Unlock()
+		return
+	}
+
+	first := len(c.errors) == 0
+	if first {
+		c.errors = append(c.errors, err)
+	}
+
+	c.mutex.Unlock()
+
+	if first && c.Cancel != nil {
+		c.Cancel()
+	}
+}
+
+// Err returns an error combining all errors collected so far, or nil if none
+// were collected. The returned error has a Causes method, like errors returned
+// by Join.
+//
+// If FailFast is set, the method returns the first error that was collected.
+func (c *Collector) Err() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.errors) == 0 {
+		return nil
+	}
+
+	if c.FailFast {
+		return c.errors[0]
+	}
+
+	return &multiError{
+		errors: append(make([]error, 0, len(c.errors)), c.errors...),
+	}
+}
+
+// JoinBuilder combines errors reported by different subsystems into a single
+// error, where each error is tagged with the name of the subsystem it came from.
+// The zero-value is ready to use.
+//
+//	b := errors.JoinBuilder{}
+//	b.Add("database", db.Close())
+//	b.Add("cache", cache.Close())
+//	err := b.Err()
+//
+// Unlike Collector, JoinBuilder is not safe for concurrent use.
+type JoinBuilder struct {
+	errors []error
+}
+
+// Add records err with a "subsystem" tag set to the given name, nil errors are
+// ignored.
+func (b *JoinBuilder) Add(subsystem string, err error) {
+	if err != nil {
+		b.errors = append(b.errors, WithTags(err, T("subsystem", subsystem)))
+	}
+}
+
+// Err returns an error combining all errors added to the builder, or nil if none
+// were added. The returned error has a Causes method, like errors returned by
+// Join.
+func (b *JoinBuilder) Err() error {
+	return Join(b.errors...)
+}
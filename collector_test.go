@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestCollector(t *testing.T) {
+	c := Collector{}
+
+	if err := c.Err(); err != nil {
+		t.Fatal("empty collector returned a non-nil error:", err)
+	}
+
+	const n = 100
+	wg := sync.WaitGroup{}
+
+	for i := 0; i != n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				c.Collect(nil)
+			} else {
+				c.Collect(fmt.Errorf("error %d", i))
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	causes := Causes(c.Err())
+	if len(causes) != n/2 {
+		t.Fatalf("bad number of collected errors: %d != %d", len(causes), n/2)
+	}
+
+	found := make([]string, len(causes))
+	for i, cause := range causes {
+		found[i] = cause.Error()
+	}
+	sort.Strings(found)
+
+	expected := make([]string, 0, n/2)
+	for i := 1; i < n; i += 2 {
+		expected = append(expected, fmt.Sprintf("error %d", i))
+	}
+	sort.Strings(expected)
+
+	for i := range expected {
+		if found[i] != expected[i] {
+			t.Errorf("missing collected error: %q", expected[i])
+		}
+	}
+}
+
+func TestJoinBuilder(t *testing.T) {
+	b := JoinBuilder{}
+
+	if err := b.Err(); err != nil {
+		t.Fatal("empty builder returned a non-nil error:", err)
+	}
+
+	b.Add("database", New("connection lost"))
+	b.Add("cache", nil)
+	b.Add("queue", WithTypes(New("queue full"), "Throttled"))
+
+	causes := Causes(b.Err())
+	if len(causes) != 2 {
+		t.Fatalf("bad number of causes: %d", len(causes))
+	}
+
+	for i, subsystem := range []string{"database", "queue"} {
+		if tag := LookupTag(causes[i], "subsystem"); tag != subsystem {
+			t.Errorf("bad subsystem tag for cause #%d: %q != %q", i, tag, subsystem)
+		}
+	}
+
+	if !Is("Throttled", causes[1]) {
+		t.Error("the types of the subsystem error were not preserved")
+	}
+}
+
+func TestCollectorFailFast(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := Collector{FailFast: true, Cancel: cancel}
+
+	c.Collect(nil)
+	if ctx.Err() != nil {
+		t.Fatal("collecting a nil error canceled the context")
+	}
+
+	err1 := New("first")
+	err2 := New("second")
+	c.Collect(err1)
+	c.Collect(err2)
+
+	if ctx.Err() == nil {
+		t.Error("collecting the first error did not cancel the context")
+	}
+
+	if err := c.Err(); err != err1 {
+		t.Errorf("bad error: %v", err)
+	}
+}
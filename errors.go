@@ -3,7 +3,9 @@ package errors
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // TODO is a non-nil error intended to act as a placeholder during development
@@ -11,6 +13,26 @@ import (
 // to be written.
 var TODO error
 
+// NotImplemented returns an error of type "NotImplemented" which reports that
+// the given feature is not implemented. The returned error carries a capture of
+// the stack trace.
+//
+// Unlike TODO, the returned error can be classified and tells where the code
+// path that isn't implemented yet was reached:
+//
+//	err = errors.NotImplemented("batch uploads")
+//
+func NotImplemented(feature string) error {
+	return &errorWithTypes{
+		cause: &baseError{
+			msg:   feature + " is not implemented",
+			stack: CaptureStackTrace(1),
+			meta:  captureMetadata(),
+		},
+		types: []string{"NotImplemented"},
+	}
+}
+
 // New returns an error that formats as the given message. The returned error
 // carries a capture of the stack trace.
 //
@@ -20,6 +42,7 @@ func New(msg string) error {
 	return &baseError{
 		msg:   msg,
 		stack: CaptureStackTrace(1),
+		meta:  captureMetadata(),
 	}
 }
 
@@ -32,6 +55,52 @@ func Errorf(msg string, args ...interface{}) error {
 	return &baseError{
 		msg:   fmt.Sprintf(msg, args...),
 		stack: CaptureStackTrace(1),
+		meta:  captureMetadata(),
+	}
+}
+
+// NewNoStack is similar to New but the returned error does not carry a stack
+// trace. It is intended for hot code paths and tests that create many errors and
+// have no use for the stack traces, which are expensive to capture.
+//
+//	err = errors.NewNoStack("something went wrong")
+//
+func NewNoStack(msg string) error {
+	return &baseError{
+		msg:  msg,
+		meta: captureMetadata(),
+	}
+}
+
+// ErrorfNoStack is similar to Errorf but the returned error does not carry a
+// stack trace.
+//
+//	err = errors.ErrorfNoStack("unexpected answer: %d", 42)
+//
+func ErrorfNoStack(msg string, args ...interface{}) error {
+	return &baseError{
+		msg:  fmt.Sprintf(msg, args...),
+		meta: captureMetadata(),
+	}
+}
+
+// FromTypes returns an error that formats as the given message and implements
+// the given types. The returned error carries a capture of the stack trace.
+//
+// This function is intended to reconstruct errors received from protocols that
+// only transmit the message and types of errors, Value.Err should be used when
+// the full error value is available.
+//
+//	err = errors.FromTypes("request timed out", "Timeout", "Temporary")
+//
+func FromTypes(msg string, types ...string) error {
+	return &errorWithTypes{
+		cause: &baseError{
+			msg:   msg,
+			stack: CaptureStackTrace(1),
+			meta:  captureMetadata(),
+		},
+		types: copyTypes(types),
 	}
 }
 
@@ -105,6 +174,203 @@ func WithTags(err error, tags ...Tag) error {
 	}
 }
 
+// AppendTags is similar to WithTags but when err was returned by a call to
+// WithTags or AppendTags, the returned error shares the cause of err and carries
+// both the tags of err and the given tags instead of adding another wrapper.
+// This avoids growing the chain of causes of errors that are tagged repeatedly.
+//
+// The tags of the returned error are the same as if WithTags had been called.
+// If err is nil the function returns nil.
+func AppendTags(err error, tags ...Tag) error {
+	if e, ok := err.(*errorWithTags); ok {
+		merged := make([]Tag, 0, len(tags)+len(e.tags))
+		merged = append(merged, makeTags(tags...)...)
+		merged = append(merged, e.tags...)
+		return &errorWithTags{
+			cause: e.cause,
+			tags:  merged,
+		}
+	}
+	return WithTags(err, tags...)
+}
+
+// WithTagsMap is similar to WithTags but takes the tags as a map of names to
+// values. The tags are sorted by name so the error has a deterministic output.
+// If err is nil the function returns nil.
+//
+// The error is adapted before tags are added.
+func WithTagsMap(err error, tags map[string]string) error {
+	if err == nil {
+		return nil
+	}
+	return &errorWithTags{
+		cause: Adapt(err),
+		tags:  makeTagsFromMap(tags),
+	}
+}
+
+// WithoutTags returns an error that wraps err and masks the tags with the given
+// names, which are not returned anymore by functions like Tags, and are not
+// part of the formatted error or of its Value. The causes of err are unchanged.
+// If err is nil the function returns nil.
+//
+// This is useful to redact tags before passing an error to another system:
+//
+//	err = errors.WithoutTags(err, "email", "ip")
+//
+// The error is adapted before tags are masked.
+func WithoutTags(err error, names ...string) error {
+	if err == nil {
+		return nil
+	}
+	masked := copyTypes(names)
+	for i, name := range masked {
+		masked[i] = normalizeTagName(name)
+	}
+	return &errorWithoutTags{
+		cause: Adapt(err),
+		names: masked,
+	}
+}
+
+// WithoutTypes returns an error that wraps err and masks the given types, which
+// are not returned anymore by functions like Types, and for which Is returns
+// false. The causes of err are unchanged. If err is nil the function returns
+// nil.
+//
+// The error is adapted before types are masked.
+func WithoutTypes(err error, types ...string) error {
+	if err == nil {
+		return nil
+	}
+	return &errorWithoutTypes{
+		cause: Adapt(err),
+		types: copyTypes(types),
+	}
+}
+
+// WithoutStack returns an error that wraps err and masks the stack traces of err
+// and its causes, which are not part of the formatted error or of its Value
+// anymore. The messages, types, tags, and causes of err are unchanged. If err
+// is nil the function returns nil.
+//
+// This is useful at boundaries between layers of a program that each capture
+// stack traces, to only keep the one captured by the boundary:
+//
+//	err = errors.WithStack(errors.WithoutStack(err))
+//
+// The error is adapted before stack traces are masked.
+func WithoutStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &errorWithoutStack{
+		cause: Adapt(err),
+	}
+}
+
+// WithRetryAfter returns an error that wraps err and carries the duration that
+// a program should wait before retrying the operation that failed. If err is
+// nil the function returns nil.
+//
+//	err = errors.WithRetryAfter(err, 30*time.Second)
+//
+// The error is adapted before the duration is added.
+func WithRetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &errorWithRetryAfter{
+		cause:      Adapt(err),
+		retryAfter: d,
+	}
+}
+
+// RetryAfter returns the duration that a program should wait before retrying
+// the operation that caused err, and a boolean indicating whether such duration
+// was found.
+//
+// The function walks through the graph of causes and returns the first positive
+// duration exposed by an error with a RetryAfter() time.Duration method, which
+// errors returned by WithRetryAfter have.
+func RetryAfter(err error) (time.Duration, bool) {
+	var d time.Duration
+	Find(err, func(e error) bool {
+		d = retryAfter(e)
+		return d > 0
+	})
+	return d, d > 0
+}
+
+// ParseRetryAfter parses the value of a Retry-After HTTP header, which is either
+// a number of seconds or a HTTP date, and returns the duration that a program
+// should wait before retrying. Dates are compared to the time returned by Now.
+// The function returns zero if s is empty, malformed, or refers to the past.
+//
+// The function is intended to be used by adapters constructing errors from HTTP
+// responses, together with WithRetryAfter or a RetryAfter method.
+func ParseRetryAfter(s string) time.Duration {
+	if len(s) == 0 {
+		return 0
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if n > 0 {
+			return time.Duration(n) * time.Second
+		}
+		return 0
+	}
+	// Same formats as http.ParseTime, without depending on net/http.
+	for _, layout := range [...]string{"Mon, 02 Jan 2006 15:04:05 GMT", time.RFC850, time.ANSIC} {
+		if t, err := time.Parse(layout, s); err == nil {
+			if d := t.Sub(Now()); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// WithContext returns an error that wraps err and carries value under the given
+// key, which can be retrieved by calling Context. If err is nil the function
+// returns nil.
+//
+// Context values are meant to hold local debugging information which does not
+// fit in tags, like the structure of a request or a retry counter:
+//
+//	err = errors.WithContext(err, "request", req)
+//
+// Unlike tags, context values are not part of the error output, and they are
+// NOT serialized by ValueOf since they may not be serializable.
+//
+// The error is adapted before the value is added.
+func WithContext(err error, key string, value interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &errorWithContext{
+		cause: Adapt(err),
+		key:   key,
+		value: value,
+	}
+}
+
+// Context returns the value set under key by a call to WithContext on err or
+// one of its causes, and a boolean indicating whether the key was found. When
+// the key was set multiple times, the outermost value is returned.
+func Context(err error, key string) (interface{}, bool) {
+	var value interface{}
+	found := Find(err, func(e error) bool {
+		c, ok := e.(*errorWithContext)
+		if ok && c.key == key {
+			value = c.value
+			return true
+		}
+		return false
+	})
+	return value, found != nil
+}
+
 // Wrap returns an error that wraps err with msg as prefix to its original
 // message and a capture of the stack trace at the time the function is called.
 // If err is nil, Wrap returns nil.
@@ -127,6 +393,70 @@ func Wrapf(err error, msg string, args ...interface{}) error {
 	return wrap(err, 1, fmt.Sprintf(msg, args...))
 }
 
+// WrapTyped is similar to Wrap but the returned error also carries the given
+// types, it is equivalent to calling WithTypes(Wrap(err, msg), types...). If err
+// is nil, WrapTyped returns nil.
+//
+//	err = errors.WrapTyped(err, "failed to fetch", "Temporary")
+//
+// The error is adapted before being wrapped with a message and stack trace.
+func WrapTyped(err error, msg string, types ...string) error {
+	if err == nil {
+		return nil
+	}
+	return &errorWithTypes{
+		cause: wrap(err, 1, msg),
+		types: copyTypes(types),
+	}
+}
+
+// WrapDistinct is similar to Wrap but only prefixes the error message with msg
+// if it differs from the outermost message of err, which prevents repeating the
+// same context when it is added by multiple layers of a program. When the
+// messages are identical, the returned error only wraps err with a capture of
+// the stack trace. If err is nil, WrapDistinct returns nil.
+//
+//	err = errors.WrapDistinct(err, "not found")
+//
+// The error is adapted before being wrapped with a message and stack trace.
+func WrapDistinct(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	if outermostMessage(err) == msg {
+		return WithStackTrace(err, CaptureStackTrace(1))
+	}
+	return wrap(err, 1, msg)
+}
+
+func outermostMessage(err error) string {
+	var path errorPath
+
+	for err != nil && !path.contains(err) {
+		path = append(path, err)
+
+		if msg := message(err); len(msg) != 0 {
+			return msg
+		}
+
+		switch e := err.(type) {
+		case errorCauses:
+			return ""
+
+		case errorCause:
+			err = e.Cause()
+
+		case errorMessage:
+			return ""
+
+		default:
+			return e.Error()
+		}
+	}
+
+	return ""
+}
+
 func wrap(err error, depth int, msg string) error {
 	if err == nil {
 		return nil
@@ -149,6 +479,14 @@ func wrap(err error, depth int, msg string) error {
 //	err = errors.Join(err1, err2, err3)
 //
 // All errors passed to the function are adapted.
+//
+// The returned error also has an Unwrap() []error method, like errors returned
+// by the Join function of the standard library since Go 1.20, so the Is and As
+// functions of the standard library traverse its causes. Unlike the standard
+// library, the messages of the errors are separated by "; " instead of newlines
+// in the output of the Error method. Unwrap returns the errors as they were
+// passed to Join, before they were adapted, because the wrappers returned by
+// adapters are not known to the standard library.
 func Join(errs ...error) error {
 	n := 0
 
@@ -168,13 +506,41 @@ func Join(errs ...error) error {
 
 	for _, err := range errs {
 		if err != nil {
-			e.errors = append(e.errors, Adapt(err))
+			adapted := Adapt(err)
+
+			if e.unwrap == nil && !sameError(adapted, err) {
+				e.unwrap = append(make([]error, 0, n), e.errors...)
+			}
+
+			if e.unwrap != nil {
+				e.unwrap = append(e.unwrap, err)
+			}
+
+			e.errors = append(e.errors, adapted)
 		}
 	}
 
 	return e
 }
 
+// JoinWithTags is like Join but the returned error also carries the given tags,
+// which apply to the whole group of errors, while the tags of each error are
+// preserved.
+//
+//	err = errors.JoinWithTags([]errors.Tag{errors.T("batch-id", id)}, errs...)
+//
+// The function returns nil if all errors passed as argument are nil.
+func JoinWithTags(tags []Tag, errs ...error) error {
+	err := Join(errs...)
+	if err == nil || len(tags) == 0 {
+		return err
+	}
+	return &errorWithTags{
+		cause: err,
+		tags:  makeTags(tags...),
+	}
+}
+
 // Recv reads all errors from the given channel and returns one that combines
 // them. All nil error are ignored.
 //
@@ -209,6 +575,128 @@ func Recv(ch <-chan error) error {
 	}
 }
 
+// RecvFirst reads errors from the given channel until it receives a non-nil
+// error, which is returned. If the channel is closed before any non-nil error
+// was received, the function returns nil.
+//
+// Once the first error was received, the remaining values are drained from the
+// channel by a background goroutine until it is closed, so the senders never
+// block forever. The program must still close the channel to release it.
+//
+// The returned error is adapted.
+func RecvFirst(ch <-chan error) error {
+	for err := range ch {
+		if err != nil {
+			go drain(ch)
+			return Adapt(err)
+		}
+	}
+	return nil
+}
+
+func drain(ch <-chan error) {
+	for range ch {
+	}
+}
+
+// RecvTimeout is similar to Recv but stops reading from ch after d elapsed. If
+// the channel was not closed before the deadline, the returned error combines
+// the errors received so far with an error of types "RecvTimeout" and
+// "Timeout", which distinguishes it from timeouts reported by the senders.
+//
+//	err := errors.RecvTimeout(ch, 10*time.Second)
+//
+//	if errors.Is("RecvTimeout", err) {
+//		// some of the tasks have not reported their result
+//	}
+//
+// All errors received on the channel are adapted.
+func RecvTimeout(ch <-chan error, d time.Duration) error {
+	var errs []error
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	for {
+		select {
+		case err, ok := <-ch:
+			if !ok {
+				if len(errs) == 0 {
+					return nil
+				}
+				return &multiError{
+					errors: errs,
+				}
+			}
+			if err != nil {
+				errs = append(errs, Adapt(err))
+			}
+
+		case <-timer.C:
+			return &multiError{
+				errors: append(errs, &errorWithTypes{
+					cause: &baseError{
+						msg:   fmt.Sprintf("timed out after %s waiting for errors", d),
+						stack: CaptureStackTrace(1),
+						meta:  captureMetadata(),
+					},
+					types: []string{"RecvTimeout", "Timeout"},
+				}),
+			}
+		}
+	}
+}
+
+// SafeGo runs fn in a new goroutine and sends the error it returned to ch, or
+// nil if it succeeded. If fn panics, the panic is recovered and converted to an
+// error of type "Panic", with the value passed to panic as cause and a capture
+// of the stack trace of the goroutine at the time it panicked.
+//
+// SafeGo is intended to be used in combination with Recv to make fan-out of
+// tasks safe from panics. Each call sends exactly one value to ch after fn
+// returned, so ch must only be closed once all values were received; in
+// particular, a sync.WaitGroup decremented by fn does not guarantee that the
+// value was sent:
+//
+//	results := make(chan error)
+//	ch := make(chan error)
+//
+//	for _, t := range tasks {
+//		errors.SafeGo(results, t)
+//	}
+//
+//	go func() {
+//		for range tasks {
+//			ch <- <-results
+//		}
+//		close(ch)
+//	}()
+//
+//	err := errors.Recv(ch)
+//
+func SafeGo(ch chan<- error, fn func() error) {
+	go func() {
+		var err error
+		defer func() { ch <- err }()
+		defer func() {
+			if v := recover(); v != nil {
+				err = recoverError(v)
+			}
+		}()
+		err = fn()
+	}()
+}
+
+func recoverError(v interface{}) error {
+	return &errorWithTypes{
+		cause: &errorWithMessage{
+			cause: Adapt(makeErrWithStack(v, 1)),
+			msg:   "panic",
+		},
+		types: []string{"Panic"},
+	}
+}
+
 // Err constructs an error from a value of arbitrary type, using the following
 // rules:
 //
@@ -224,148 +712,804 @@ func Recv(ch <-chan error) error {
 // - if v doesn't fall into any of those categories, the function behaves like
 // calling Errorf("%+v", v).
 //
-// A common use case for this function is to implement internal error reporting
-// based on raising panics (within a package), here is an example:
+// A common use case for this function is to implement internal error reporting
+// based on raising panics (within a package), here is an example:
+//
+//	func F() (err error) {
+//		defer func() { err = errors.Err(recover()) }()
+//		// ...
+//	}
+//
+func Err(v interface{}) error {
+	return makeErr(v, 1)
+}
+
+// ErrWithStack is similar to Err but ensures that the returned error carries a
+// stack trace. When v is an error which has no stack trace, the function wraps
+// it with a capture of the stack trace at the time it was called.
+//
+// When used to recover from panics, the stack trace includes the function which
+// triggered the panic:
+//
+//	func F() (err error) {
+//		defer func() { err = errors.ErrWithStack(recover()) }()
+//		// ...
+//	}
+//
+func ErrWithStack(v interface{}) error {
+	return makeErrWithStack(v, 1)
+}
+
+func makeErrWithStack(v interface{}, depth int) error {
+	err := makeErr(v, depth+1)
+
+	if err != nil && !HasAnyStack(err) {
+		err = &errorWithStack{
+			cause: Adapt(err),
+			stack: CaptureStackTrace(depth + 1),
+		}
+	}
+
+	return err
+}
+
+func makeErr(v interface{}, depth int) error {
+	switch value := v.(type) {
+	case nil:
+		return nil
+
+	case string:
+		return &baseError{
+			msg:   value,
+			stack: CaptureStackTrace(depth + 1),
+			meta:  captureMetadata(),
+		}
+
+	case error:
+		return value
+
+	case interface {
+		Err() error
+	}:
+		return value.Err()
+
+	default:
+		return &baseError{
+			msg:   fmt.Sprintf("%+v", value),
+			stack: CaptureStackTrace(depth + 1),
+			meta:  captureMetadata(),
+		}
+	}
+}
+
+// Normalize returns a canonical form of err where redundant adjacent wrappers
+// of the same kind are collapsed into one. Two stack traces in a row are
+// reduced to the innermost one, and two layers setting the same list of types
+// are reduced to a single layer.
+//
+// The messages, types, tags, and causes of the returned error are the same as
+// the ones of err. Errors that were not created by this package are returned
+// unchanged, the function does not look into their causes.
+func Normalize(err error) error {
+	switch e := err.(type) {
+	case *errorWithStack:
+		cause := Normalize(e.cause)
+		if c, ok := cause.(*errorWithStack); ok {
+			return c
+		}
+		if sameError(cause, e.cause) {
+			return e
+		}
+		return &errorWithStack{cause: cause, stack: e.stack}
+
+	case *errorWithTypes:
+		cause := Normalize(e.cause)
+		if c, ok := cause.(*errorWithTypes); ok && equalStrings(c.types, e.types) {
+			return c
+		}
+		if sameError(cause, e.cause) {
+			return e
+		}
+		return &errorWithTypes{cause: cause, types: e.types}
+
+	case *errorWithMessage:
+		if cause := Normalize(e.cause); !sameError(cause, e.cause) {
+			return &errorWithMessage{cause: cause, msg: e.msg}
+		}
+
+	case *errorWithTags:
+		if cause := Normalize(e.cause); !sameError(cause, e.cause) {
+			return &errorWithTags{cause: cause, tags: e.tags}
+		}
+
+	case *errorWithRetryAfter:
+		if cause := Normalize(e.cause); !sameError(cause, e.cause) {
+			return &errorWithRetryAfter{cause: cause, retryAfter: e.retryAfter}
+		}
+
+	case *errorWithoutTags:
+		if cause := Normalize(e.cause); !sameError(cause, e.cause) {
+			return &errorWithoutTags{cause: cause, names: e.names}
+		}
+
+	case *errorWithoutTypes:
+		if cause := Normalize(e.cause); !sameError(cause, e.cause) {
+			return &errorWithoutTypes{cause: cause, types: e.types}
+		}
+
+	case *errorWithoutStack:
+		if cause := Normalize(e.cause); !sameError(cause, e.cause) {
+			return &errorWithoutStack{cause: cause}
+		}
+
+	case *errorWithContext:
+		if cause := Normalize(e.cause); !sameError(cause, e.cause) {
+			return &errorWithContext{cause: cause, key: e.key, value: e.value}
+		}
+
+	case *multiError:
+		var errs []error
+		for i, cause := range e.errors {
+			if c := Normalize(cause); !sameError(c, cause) {
+				if errs == nil {
+					errs = append(make([]error, 0, len(e.errors)), e.errors...)
+				}
+				errs[i] = c
+			}
+		}
+		if errs != nil {
+			return &multiError{errors: errs, unwrap: e.unwrap}
+		}
+	}
+
+	return err
+}
+
+func equalStrings(s1, s2 []string) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+	for i := range s1 {
+		if s1[i] != s2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Cause returns the cause of err, which may be err if it had no cause.
+//
+// If the chain of causes forms a cycle, the function returns the last error
+// found before reaching an error that was already visited.
+func Cause(err error) error {
+	var path errorPath
+	for {
+		if e, ok := err.(errorCause); ok {
+			path = append(path, err)
+			if cause := e.Cause(); cause != nil && !path.contains(cause) {
+				err = cause
+				continue
+			}
+		}
+		return err
+	}
+}
+
+// Causes returns the list of causes of err, which may be an empty slice if err
+// is nil or had no causes.
+func Causes(err error) []error {
+	var path errorPath
+	original := err
+	for {
+		if e, ok := err.(errorCauses); ok {
+			return e.Causes()
+		}
+		if e, ok := err.(errorCause); ok {
+			path = append(path, err)
+			if cause := e.Cause(); cause != nil && !path.contains(cause) {
+				err = cause
+				continue
+			}
+		}
+		if err != original {
+			return []error{err}
+		}
+		return nil
+	}
+}
+
+// Range calls fn with each cause of err, in the order returned by Causes, and
+// stops when fn returns false. It is a shorthand to scan the causes of errors
+// for the first one matching a condition:
+//
+//	errors.Range(err, func(cause error) bool {
+//		if errors.Is("Timeout", cause) {
+//			found = cause
+//		}
+//		return found == nil
+//	})
+//
+func Range(err error, fn func(error) bool) {
+	for _, cause := range Causes(err) {
+		if !fn(cause) {
+			return
+		}
+	}
+}
+
+// Is tests whether err is of type typ. Errors may implement types by defining
+// methods that take no arguments and return a boolean value. Passing the name
+// of those methods to Is tests for their existence and calls them to validate
+// the type of the error.
+//
+// This model has been used in the standard library where some errors implement
+// the Temporary and Timeout methods to give the program more details about the
+// reason the error occurred and the way it should be handled.
+//
+// Here is an example of using the Is function:
+//
+//	if errors.Is("Timeout", err) {
+//		// ...
+//	}
+//	if errors.Is("Temporary", err) {
+//		// ...
+//	}
+//
+// The function walks through the graph of causes looking for an error which may
+// implement the given type. When an error has multiple causes, Is returns true
+// if any of them is of type typ, use AllCausesHaveType to test whether all of
+// them are.
+func Is(typ string, err error) bool {
+	if types, ok := cachedTypes(err); ok {
+		return containsType(types, typ)
+	}
+	return is(typ, err, nil)
+}
+
+func is(typ string, err error, path errorPath) bool {
+	if err == nil || path.contains(err) || masksType(err, typ) {
+		return false
+	}
+
+	if e, ok := err.(errorTypes); ok {
+		for _, t := range e.Types() {
+			if t == typ {
+				return true
+			}
+		}
+	}
+
+	if f := typeMethod(typ, err); f != nil {
+		return callTypeMethod(f)
+	}
+
+	path = append(path, err)
+
+	switch e := err.(type) {
+	case errorCause:
+		return is(typ, e.Cause(), path)
+
+	case errorCauses:
+		for _, cause := range e.Causes() {
+			if ok := is(typ, cause, path); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// AllCausesHaveType tests whether all causes of err are of type typ, which
+// differs from Is when err has multiple causes: Is returns true if any of the
+// causes has the type. For example, a program retrying a batch of operations
+// may only retry the whole batch if all the errors were temporary:
+//
+//	if errors.AllCausesHaveType(err, "Temporary") {
+//		// ...
+//	}
+//
+// Causes that have multiple causes themselves are tested recursively. As with
+// Is, when an error on the way to the causes defines the type, the result is
+// decided by this error. If err has no causes, the function behaves like Is.
+func AllCausesHaveType(err error, typ string) bool {
+	return allCausesHaveType(typ, err, nil)
+}
+
+func allCausesHaveType(typ string, err error, path errorPath) bool {
+	if err == nil || path.contains(err) || masksType(err, typ) {
+		return false
+	}
+
+	if e, ok := err.(errorTypes); ok {
+		for _, t := range e.Types() {
+			if t == typ {
+				return true
+			}
+		}
+	}
+
+	if f := typeMethod(typ, err); f != nil {
+		return callTypeMethod(f)
+	}
+
+	path = append(path, err)
+
+	switch e := err.(type) {
+	case errorCause:
+		return allCausesHaveType(typ, e.Cause(), path)
+
+	case errorCauses:
+		causes := e.Causes()
+		for _, cause := range causes {
+			if !allCausesHaveType(typ, cause, path) {
+				return false
+			}
+		}
+		return len(causes) != 0
+	}
+
+	return false
+}
+
+// Find walks the graph of causes of err in depth-first order and returns the
+// first error for which match returns true, or nil if none matched.
+//
+//	e := errors.Find(err, func(e error) bool {
+//		_, ok := e.(*DomainError)
+//		return ok
+//	})
+//
+func Find(err error, match func(error) bool) error {
+	var found error
+	Walk(err, func(e error) bool {
+		if found == nil && match(e) {
+			found = e
+		}
+		return found == nil
+	})
+	return found
+}
+
+// FindType walks the graph of causes of err in depth-first order and returns
+// the first error which has the type typ, or nil if none had it.
+//
+// Unlike Is, the function only considers the types carried by each error, not
+// the types that an error inherits from its causes.
+func FindType(err error, typ string) error {
+	var found error
+	Walk(err, func(e error) bool {
+		if found == nil && hasType(typ, e) {
+			found = e
+		}
+		return found == nil && !masksType(e, typ)
+	})
+	return found
+}
+
+// CauseOfType returns the outermost error in the graph of causes of err which is
+// classified as typ, or nil if there were none. For example, if err is a HTTP
+// error wrapped with a message, CauseOfType(err, "Temporary") returns the HTTP
+// error.
+//
+// The function follows the same rules as Is, the returned error is the one that
+// makes Is(typ, err) return true: when an error defines a method for typ, the
+// search does not continue into its causes, even if the method returns false.
+// This differs from FindType which considers all errors in the graph.
+func CauseOfType(err error, typ string) error {
+	return causeOfType(typ, err, nil)
+}
+
+func causeOfType(typ string, err error, path errorPath) error {
+	if err == nil || path.contains(err) || masksType(err, typ) {
+		return nil
+	}
+
+	if e, ok := err.(errorTypes); ok {
+		for _, t := range e.Types() {
+			if t == typ {
+				return err
+			}
+		}
+	}
+
+	if f := typeMethod(typ, err); f != nil {
+		if callTypeMethod(f) {
+			return err
+		}
+		return nil
+	}
+
+	path = append(path, err)
+
+	switch e := err.(type) {
+	case errorCause:
+		return causeOfType(typ, e.Cause(), path)
+
+	case errorCauses:
+		for _, cause := range e.Causes() {
+			if found := causeOfType(typ, cause, path); found != nil {
+				return found
+			}
+		}
+	}
+
+	return nil
+}
+
+// Extract walks the graph of causes of err in depth-first order and finds the
+// first error that is assignable to the value pointed to by target. If one is
+// found, the function sets target to that error value and returns true,
+// otherwise it returns false.
+//
+//	var e *DomainError
+//	if errors.Extract(err, &e) {
+//		// ...
+//	}
+//
+// The function follows the same rules as the As function of the standard
+// library, but it traverses all the branches of errors with multiple causes.
+// An error is also considered a match if it has an As(interface{}) bool method
+// which returns true when called with target.
+//
+// The function panics if target is not a non-nil pointer to either a type that
+// implements error, or to any interface type.
+func Extract(err error, target interface{}) bool {
+	return Find(err, targetMatcher(target)) != nil
+}
+
+// AsNearest is like Extract but walks the graph of causes of err in
+// breadth-first order, so when errors assignable to target exist at different
+// depths, the one closest to err is selected. This is usually what programs
+// intend when the same type of error may appear on multiple branches of errors
+// created by functions like Join.
+//
+// The function panics if target is not a non-nil pointer to either a type that
+// implements error, or to any interface type.
+func AsNearest(err error, target interface{}) bool {
+	return findNearest(err, targetMatcher(target)) != nil
+}
+
+func findNearest(err error, match func(error) bool) error {
+	var visited errorPath
+
+	for queue := []error{err}; len(queue) != 0; {
+		e := queue[0]
+		queue = queue[1:]
+
+		if e == nil || visited.contains(e) {
+			continue
+		}
+
+		if match(e) {
+			return e
+		}
+
+		visited = append(visited, e)
+
+		switch x := e.(type) {
+		case errorCause:
+			queue = append(queue, x.Cause())
+		case errorCauses:
+			queue = append(queue, x.Causes()...)
+		}
+	}
+
+	return nil
+}
+
+func targetMatcher(target interface{}) func(error) bool {
+	if target == nil {
+		panic("errors: target cannot be nil")
+	}
+
+	val := reflect.ValueOf(target)
+	typ := val.Type()
+
+	if typ.Kind() != reflect.Ptr || val.IsNil() {
+		panic("errors: target must be a non-nil pointer")
+	}
+
+	targetType := typ.Elem()
+
+	if targetType.Kind() != reflect.Interface && !targetType.Implements(errorType) {
+		panic("errors: *target must be interface or implement error")
+	}
+
+	return func(e error) bool {
+		if reflect.TypeOf(e).AssignableTo(targetType) {
+			val.Elem().Set(reflect.ValueOf(e))
+			return true
+		}
+		if x, ok := e.(interface {
+			As(interface{}) bool
+		}); ok && x.As(target) {
+			return true
+		}
+		return false
+	}
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// HasAnyStack returns true if err or any of its causes carries a stack trace.
+//
+// Programs may use this function to detect errors that crossed a boundary
+// without ever being wrapped by a function capturing a stack trace. Stack
+// traces masked by WithoutStack are ignored.
+func HasAnyStack(err error) bool {
+	found := false
+	walkPath(err, nil, func(e error) bool {
+		if _, masked := e.(*errorWithoutStack); masked {
+			return false
+		}
+		found = len(stackTrace(e)) != 0
+		return !found
+	})
+	return found
+}
+
+// Types returns a slice containing all the types implemented by err and its
+// causes (if it had any).
+func Types(err error) []string {
+	if types, ok := cachedTypes(err); ok {
+		return copyTypes(types)
+	}
+	return deepAppendTypes(nil, err)
+}
+
+// Ignore returns nil if err is of any of the given types, otherwise it returns
+// err unchanged.
+//
+//	return errors.Ignore(deleteObject(key), "NotFound")
+//
+func Ignore(err error, types ...string) error {
+	for _, typ := range types {
+		if Is(typ, err) {
+			return nil
+		}
+	}
+	return err
+}
+
+// IsAnyOf tests whether err has at least one of the given types.
+func IsAnyOf(err error, types []string) bool {
+	return len(TypesIntersect(err, types)) != 0
+}
+
+// TypesIntersect returns the list of types in the given slice that err has, in
+// the order they were given. It returns nil if err had none of those types.
+//
+// The function is useful when the list of types that a program handles comes
+// from a configuration:
 //
-//	func F() (err error) {
-//		defer func() { err = errors.Err(recover()) }()
+//	if types := errors.TypesIntersect(err, config.RetryableTypes); len(types) != 0 {
 //		// ...
 //	}
 //
-func Err(v interface{}) error {
-	switch value := v.(type) {
-	case nil:
-		return nil
+func TypesIntersect(err error, types []string) []string {
+	var intersect []string
+	errTypes := Types(err)
 
-	case string:
-		return &baseError{
-			msg:   value,
-			stack: CaptureStackTrace(1),
+	for _, t := range types {
+		if containsType(errTypes, t) && !containsType(intersect, t) {
+			intersect = append(intersect, t)
 		}
+	}
 
-	case error:
-		return value
+	return intersect
+}
 
-	case interface {
-		Err() error
-	}:
-		return value.Err()
+// ConcreteTypes is similar to Types but excludes the given types from the
+// returned slice. When no types are given, the high-level types derived by
+// the adapters of this package ("Conflict", "NotFound", "PermissionDenied",
+// "Temporary", "Throttled", "Timeout", "Unauthenticated", and "Validation") are
+// excluded, leaving only the concrete classification of err.
+//
+//	types := errors.ConcreteTypes(err) // [ServiceUnavailable]
+//
+func ConcreteTypes(err error, exclude ...string) []string {
+	if len(exclude) == 0 {
+		exclude = highLevelTypes
+	}
 
-	default:
-		return &baseError{
-			msg:   fmt.Sprintf("%+v", value),
-			stack: CaptureStackTrace(1),
+	types := Types(err)
+	i := 0
+
+	for _, t := range types {
+		if !containsType(exclude, t) {
+			types[i] = t
+			i++
 		}
 	}
-}
 
-// Cause returns the cause of err, which may be err if it had no cause.
-func Cause(err error) error {
-	for {
-		if e, ok := err.(errorCause); ok {
-			if cause := e.Cause(); cause != nil {
-				err = cause
-				continue
-			}
-		}
-		return err
+	if i == 0 {
+		return nil
 	}
+
+	return types[:i]
 }
 
-// Causes returns the list of causes of err, which may be an empty slice if err
-// is nil or had no causes.
-func Causes(err error) []error {
-	original := err
-	for {
-		if e, ok := err.(errorCauses); ok {
-			return e.Causes()
-		}
-		if e, ok := err.(errorCause); ok {
-			if cause := e.Cause(); cause != nil {
-				err = cause
-				continue
-			}
-		}
-		if err != original {
-			return []error{err}
-		}
+// TypeHistogram returns a map of the number of leaf errors in the graph of
+// causes of err which carry each type. Leaf errors are the errors at the end of
+// each branch of the graph, they inherit the types carried by the errors on the
+// path that leads to them.
+//
+// This is useful to get a breakdown of the composition of an error aggregating
+// many failures, for example:
+//
+//	err := errors.Join(
+//		errors.WithTypes(errors.New("A"), "Timeout"),
+//		errors.WithTypes(errors.New("B"), "Timeout", "Temporary"),
+//		errors.New("C"),
+//	)
+//
+//	h := errors.TypeHistogram(err) // map[Temporary:1 Timeout:2]
+//
+// If err is nil, the function returns nil.
+func TypeHistogram(err error) map[string]int {
+	if err == nil {
 		return nil
 	}
+	h := make(map[string]int)
+	walkLeafTypes(err, nil, nil, func(types []string) {
+		for _, t := range types {
+			h[t]++
+		}
+	})
+	return h
 }
 
-// Is tests whether err is of type typ. Errors may implement types by defining
-// methods that take no arguments and return a boolean value. Passing the name
-// of those methods to Is tests for their existence and calls them to validate
-// the type of the error.
+// GroupByType returns a map of the number of leaf errors in the graph of causes
+// of err grouped by their primary type. Leaf errors are defined the same way as
+// in TypeHistogram, but unlike TypeHistogram each leaf error is only counted
+// once, so the sum of the counts is the number of leaf errors.
 //
-// This model has been used in the standard library where some errors implement
-// the Temporary and Timeout methods to give the program more details about the
-// reason the error occurred and the way it should be handled.
+// The primary type of an error is the first of its types in alphabetical order
+// after excluding the high-level types listed in the documentation of
+// ConcreteTypes, or the first of its high-level types if it has no other types.
+// Leaf errors without types are counted under the empty string.
 //
-// Here is an example of using the Is function:
+//	err := errors.Join(
+//		errors.WithTypes(errors.New("A"), "RequestTimeout", "Timeout"),
+//		errors.WithTypes(errors.New("B"), "Timeout"),
+//		errors.New("C"),
+//	)
 //
-//	if errors.Is("Timeout", err) {
-//		// ...
-//	}
-//	if errors.Is("Temporary", err) {
-//		// ...
-//	}
+//	g := errors.GroupByType(err) // map[:1 RequestTimeout:1 Timeout:1]
 //
-// The function walks through the graph of causes looking for an error which may
-// implement the given type.
-func Is(typ string, err error) bool {
+// If err is nil, the function returns nil.
+func GroupByType(err error) map[string]int {
 	if err == nil {
-		return false
+		return nil
 	}
+	g := make(map[string]int)
+	walkLeafTypes(err, nil, nil, func(types []string) {
+		g[primaryType(types)]++
+	})
+	return g
+}
 
-	if e, ok := err.(errorTypes); ok {
-		for _, t := range e.Types() {
-			if t == typ {
-				return true
-			}
+func primaryType(types []string) string {
+	for _, t := range types {
+		if !containsType(highLevelTypes, t) {
+			return t
 		}
 	}
+	if len(types) != 0 {
+		return types[0]
+	}
+	return ""
+}
 
-	v := reflect.ValueOf(err)
-	m := v.MethodByName(typ)
-
-	if m.IsValid() {
-		if f, ok := m.Interface().(func() bool); ok {
-			return f()
-		}
+// walkLeafTypes calls do with the sorted types of each leaf error in the graph
+// of causes of err, including the types inherited from their parents.
+func walkLeafTypes(err error, parent []string, path errorPath, do func([]string)) {
+	if err == nil || path.contains(err) {
+		return
 	}
 
-	switch e := err.(type) {
-	case errorCause:
-		return Is(typ, e.Cause())
+	_, types, _, _, causes := Inspect(err)
+	types = dedupeTypes(append(copyTypes(parent), types...))
 
-	case errorCauses:
-		for _, cause := range e.Causes() {
-			if ok := Is(typ, cause); ok {
-				return true
-			}
-		}
+	if len(causes) == 0 {
+		do(types)
+		return
 	}
 
-	return false
-}
+	path = append(path, err)
 
-// Types returns a slice containing all the types implemented by err and its
-// causes (if it had any).
-func Types(err error) []string {
-	return deepAppendTypes(nil, err)
+	for _, cause := range causes {
+		walkLeafTypes(cause, types, path, do)
+	}
 }
 
 // Tags returns a slice containing all the tags set on err and its causes
 // (it if had any).
+//
+// All tags are returned, including tags with the same name set on different
+// errors of the graph of causes, see DistinctTags to only get one value per tag
+// name.
 func Tags(err error) []Tag {
 	return deepAppendTags(nil, err)
 }
 
+// DistinctTags is similar to Tags but only returns one value for each tag name,
+// which is the value set on the outermost error when a tag is shadowed by an
+// error wrapping its causes.
+func DistinctTags(err error) []Tag {
+	var tags []Tag
+	var seen map[string]struct{}
+
+	walkPath(err, nil, func(err error) bool {
+		var errTags []Tag
+
+		switch e := err.(type) {
+		case *errorWithoutTags:
+			errTags = e.maskTags(DistinctTags(e.cause))
+		case errorTags:
+			errTags = tagsOf(e)
+		}
+
+		for _, tag := range errTags {
+			if _, exists := seen[tag.Name]; !exists {
+				if seen == nil {
+					seen = make(map[string]struct{})
+				}
+				seen[tag.Name] = struct{}{}
+				tags = append(tags, tag)
+			}
+		}
+
+		_, masked := err.(*errorWithoutTags)
+		return !masked
+	})
+
+	sortTags(tags)
+	return tags
+}
+
+// TagsMap returns a map of all the values of each tag set on err and its causes
+// (if it had any). The values of each tag are ordered from the outermost error
+// to the innermost, in the order that the graph of causes is walked.
+//
+// If err has no tags, the function returns nil.
+func TagsMap(err error) map[string][]string {
+	var m map[string][]string
+	walkPath(err, nil, func(err error) bool {
+		var errTags []Tag
+
+		switch e := err.(type) {
+		case *errorWithoutTags:
+			for name, values := range TagsMap(e.cause) {
+				if !containsType(e.names, name) {
+					for _, value := range values {
+						errTags = append(errTags, T(name, value))
+					}
+				}
+			}
+		case errorTags:
+			errTags = tagsOf(e)
+		}
+
+		for _, tag := range errTags {
+			if m == nil {
+				m = make(map[string][]string)
+			}
+			m[tag.Name] = append(m[tag.Name], tag.Value)
+		}
+
+		_, masked := err.(*errorWithoutTags)
+		return !masked
+	})
+	return m
+}
+
 // LookupTag returns value for a given tag name. Returns empty string if tag wasn't found.
 // If multiple tags found by that name, the most recent value is used.
 func LookupTag(err error, name string) string {
 	var result string
+	name = normalizeTagName(name)
 	for _, tag := range deepAppendTags(nil, err) {
 		if tag.Name == name {
 			result = tag.Value
@@ -379,16 +1523,36 @@ func LookupTag(err error, name string) string {
 //
 // The function follows a straight path on the error graph, stopping when it
 // finds an error that doesn't have a single cause (either zero or many).
+//
+// If the chain of causes forms a cycle, the function stops when it reaches an
+// error that was already visited.
 func Inspect(err error) (msgs []string, types []string, tags []Tag, stacks []StackTrace, causes []error) {
-	for err != nil {
-		types = appendTypes(types, err)
-		tags = appendTags(tags, err)
+	var path errorPath
+	var maskedTags, maskedTypes []string
+	var maskedStacks bool
+
+	for err != nil && !path.contains(err) {
+		path = append(path, err)
+
+		switch e := err.(type) {
+		case *errorWithoutTags:
+			maskedTags = append(maskedTags, e.names...)
+		case *errorWithoutTypes:
+			maskedTypes = append(maskedTypes, e.types...)
+		case *errorWithoutStack:
+			maskedStacks = true
+		default:
+			// The masks only apply to the errors found below them, the types
+			// and tags already collected are left untouched.
+			types = append(types, removeTypes(appendTypes(nil, err), maskedTypes)...)
+			tags = append(tags, removeTags(appendTags(nil, err), maskedTags)...)
+		}
 
 		if msg := message(err); len(msg) != 0 {
 			msgs = append(msgs, msg)
 		}
 
-		if stack := stackTrace(err); len(stack) != 0 {
+		if stack := stackTrace(err); len(stack) != 0 && !maskedStacks {
 			stacks = append(stacks, stack)
 		}
 
@@ -404,30 +1568,151 @@ func Inspect(err error) (msgs []string, types []string, tags []Tag, stacks []Sta
 			err = nil // prevent duplicating the message with the Error call
 
 		default:
-			msgs = append(msgs, e.Error())
+			if msg := e.Error(); len(msg) != 0 {
+				msgs = append(msgs, msg)
+			}
 			err = nil
 		}
 	}
 
+	if (len(maskedTags) != 0 || len(maskedTypes) != 0 || maskedStacks) && len(causes) != 0 {
+		// The masks apply to the whole graph of causes, they are carried over
+		// to the causes so the errors inspecting them also skip masked tags,
+		// types, and stack traces.
+		masked := make([]error, len(causes))
+		for i, cause := range causes {
+			if len(maskedTags) != 0 {
+				cause = &errorWithoutTags{cause: cause, names: maskedTags}
+			}
+			if len(maskedTypes) != 0 {
+				cause = &errorWithoutTypes{cause: cause, types: maskedTypes}
+			}
+			if maskedStacks {
+				cause = &errorWithoutStack{cause: cause}
+			}
+			masked[i] = cause
+		}
+		causes = masked
+	}
+
 	types = dedupeTypes(types)
 	sortTags(tags)
 	return
 }
 
+// MessageChain returns the messages of err and the errors on the straight path
+// of its causes, outermost first, skipping empty messages. The path stops at
+// the first error with zero or many causes, like Inspect.
+//
+//	err = errors.WithMessage(errors.Wrap(errors.New("inner"), "middle"), "outer")
+//	msgs := errors.MessageChain(err) // []string{"outer", "middle", "inner"}
+//
+// The messages are the ones collected by Inspect. When the path does not end at
+// an error with multiple causes, they are the ones joined by ": " in the output
+// of err.Error(), this function lets programs combine them differently.
+func MessageChain(err error) []string {
+	msgs, _, _, _, _ := Inspect(err)
+	return msgs
+}
+
+// Walk traverses the graph of causes of err in depth-first order, calling visit
+// on err and each of its causes. When visit returns false, the causes of the
+// error that was passed to it are not visited, but the traversal continues
+// with the other branches of the graph.
+//
+// Here is an example of using Walk to find the first error tagged with a name:
+//
+//	var found error
+//	errors.Walk(err, func(e error) bool {
+//		if found == nil && hasTag(e, "request_id") {
+//			found = e
+//		}
+//		return found == nil
+//	})
+//
+// Errors that were already visited on the path leading to a cause are skipped,
+// which guarantees that the function terminates on graphs containing cycles.
+func Walk(err error, visit func(err error) bool) {
+	walkPath(err, nil, visit)
+}
+
 func walk(err error, do func(error)) {
-	if err != nil {
+	walkPath(err, nil, func(err error) bool {
 		do(err)
+		return true
+	})
+}
+
+func walkPath(err error, path errorPath, visit func(error) bool) {
+	if err != nil && !path.contains(err) && visit(err) {
+		path = append(path, err)
 
 		switch e := err.(type) {
 		case errorCause:
-			walk(e.Cause(), do)
+			walkPath(e.Cause(), path, visit)
 
 		case errorCauses:
 			for _, cause := range e.Causes() {
-				walk(cause, do)
+				walkPath(cause, path, visit)
+			}
+		}
+	}
+}
+
+// errorPath is a list of errors traversed to reach a node in the graph of
+// causes, it is used to detect cycles.
+type errorPath []error
+
+func (path errorPath) contains(err error) bool {
+	for _, e := range path {
+		if sameError(e, err) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendChain appends err and the chain of its single causes to path, which
+// are the errors that Inspect collapses into a single node.
+func (path errorPath) appendChain(err error) errorPath {
+	for err != nil && !path.contains(err) {
+		path = append(path, err)
+
+		e, ok := err.(errorCause)
+		if !ok {
+			break
+		}
+
+		err = e.Cause()
+	}
+	return path
+}
+
+// exclude returns the errors of causes that are not on the path, the slice is
+// only copied if some of the causes had to be removed.
+func (path errorPath) exclude(causes []error) []error {
+	for i, cause := range causes {
+		if path.contains(cause) {
+			filtered := make([]error, i, len(causes)-1)
+			copy(filtered, causes)
+
+			for _, cause := range causes[i+1:] {
+				if !path.contains(cause) {
+					filtered = append(filtered, cause)
+				}
 			}
+
+			return filtered
 		}
 	}
+	return causes
+}
+
+// sameError compares e1 and e2, it does not panic if the errors have the same
+// dynamic type and this type is not comparable.
+func sameError(e1, e2 error) bool {
+	t := reflect.TypeOf(e1)
+	return t == reflect.TypeOf(e2) && t.Comparable() && e1 == e2
 }
 
 type errorCause interface {
@@ -454,9 +1739,15 @@ type errorStackTrace interface {
 	StackTrace() StackTrace
 }
 
+type errorRetryAfter interface {
+	RetryAfter() time.Duration
+}
+
 type baseError struct {
+	typesCache
 	msg   string
 	stack StackTrace
+	meta  *Metadata
 }
 
 func (e *baseError) Error() string {
@@ -471,18 +1762,37 @@ func (e *baseError) StackTrace() StackTrace {
 	return e.stack
 }
 
+func (e *baseError) Metadata() Metadata {
+	if e.meta == nil {
+		return Metadata{}
+	}
+	return *e.meta
+}
+
 func (e *baseError) Format(s fmt.State, v rune) {
 	format(s, v, e)
 }
 
 type multiError struct {
+	typesCache
 	errors []error
+	unwrap []error // errors before adaptation, nil if identical to errors
 }
 
 func (e *multiError) Causes() []error {
 	return e.errors
 }
 
+// Unwrap returns the same errors as Causes, but before they were adapted, it
+// makes multiError compatible with the Is and As functions of the standard
+// library.
+func (e *multiError) Unwrap() []error {
+	if e.unwrap != nil {
+		return e.unwrap
+	}
+	return e.errors
+}
+
 func (e *multiError) Error() string {
 	s := make([]string, len(e.errors))
 	for i, e := range e.errors {
@@ -496,6 +1806,7 @@ func (e *multiError) Format(s fmt.State, v rune) {
 }
 
 type errorWithMessage struct {
+	typesCache
 	cause error
 	msg   string
 }
@@ -517,6 +1828,7 @@ func (e *errorWithMessage) Format(s fmt.State, v rune) {
 }
 
 type errorWithStack struct {
+	typesCache
 	cause error
 	stack StackTrace
 }
@@ -538,6 +1850,7 @@ func (e *errorWithStack) StackTrace() StackTrace {
 }
 
 type errorWithTypes struct {
+	typesCache
 	cause error
 	types []string
 }
@@ -559,6 +1872,7 @@ func (e *errorWithTypes) Types() []string {
 }
 
 type errorWithTags struct {
+	typesCache
 	cause error
 	tags  []Tag
 }
@@ -579,6 +1893,111 @@ func (e *errorWithTags) Tags() []Tag {
 	return e.tags
 }
 
+type errorWithoutTags struct {
+	typesCache
+	cause error
+	names []string
+}
+
+func (e *errorWithoutTags) Cause() error {
+	return e.cause
+}
+
+func (e *errorWithoutTags) Error() string {
+	return e.cause.Error()
+}
+
+func (e *errorWithoutTags) Format(s fmt.State, v rune) {
+	format(s, v, e)
+}
+
+func (e *errorWithoutTags) maskTags(tags []Tag) []Tag {
+	return removeTags(tags, e.names)
+}
+
+type errorWithoutTypes struct {
+	typesCache
+	cause error
+	types []string
+}
+
+func (e *errorWithoutTypes) Cause() error {
+	return e.cause
+}
+
+func (e *errorWithoutTypes) Error() string {
+	return e.cause.Error()
+}
+
+func (e *errorWithoutTypes) Format(s fmt.State, v rune) {
+	format(s, v, e)
+}
+
+type errorWithoutStack struct {
+	typesCache
+	cause error
+}
+
+func (e *errorWithoutStack) Cause() error {
+	return e.cause
+}
+
+func (e *errorWithoutStack) Error() string {
+	return e.cause.Error()
+}
+
+func (e *errorWithoutStack) Format(s fmt.State, v rune) {
+	format(s, v, e)
+}
+
+// masksType returns true if err is an error returned by WithoutTypes which masks
+// typ.
+func masksType(err error, typ string) bool {
+	e, ok := err.(*errorWithoutTypes)
+	return ok && containsType(e.types, typ)
+}
+
+type errorWithRetryAfter struct {
+	typesCache
+	cause      error
+	retryAfter time.Duration
+}
+
+func (e *errorWithRetryAfter) Cause() error {
+	return e.cause
+}
+
+func (e *errorWithRetryAfter) Error() string {
+	return e.cause.Error()
+}
+
+func (e *errorWithRetryAfter) Format(s fmt.State, v rune) {
+	format(s, v, e)
+}
+
+func (e *errorWithRetryAfter) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+type errorWithContext struct {
+	typesCache
+	cause error
+	key   string
+	value interface{}
+}
+
+func (e *errorWithContext) Cause() error {
+	return e.cause
+}
+
+func (e *errorWithContext) Error() string {
+	return e.cause.Error()
+}
+
+func (e *errorWithContext) Format(s fmt.State, v rune) {
+	format(s, v, e)
+}
+
 type errorTODO struct{}
 
 func (*errorTODO) Error() string {
@@ -602,3 +2021,10 @@ func stackTrace(err error) StackTrace {
 	}
 	return nil
 }
+
+func retryAfter(err error) time.Duration {
+	if e, ok := err.(errorRetryAfter); ok {
+		return e.RetryAfter()
+	}
+	return 0
+}
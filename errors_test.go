@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestErrors(t *testing.T) {
@@ -370,3 +373,1788 @@ func TestLookupTag(t *testing.T) {
 		})
 	}
 }
+
+func TestCycles(t *testing.T) {
+	a := &errorWithCycle{msg: "A"}
+	b := &errorWithCycle{msg: "B", cause: a}
+	a.cause = b
+
+	j := &errorsWithCycle{}
+	j.causes = []error{New("C"), j}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		if cause := Cause(a); cause != b {
+			t.Error("bad cause of cyclic error:", cause)
+		}
+
+		if causes := Causes(a); len(causes) != 1 || causes[0] != b {
+			t.Error("bad causes of cyclic error:", causes)
+		}
+
+		if Is("Timeout", a) || Is("Timeout", j) {
+			t.Error("cyclic errors must not be of type Timeout")
+		}
+
+		if types := Types(WithTypes(a, "Temporary")); !equalTypes(types, []string{"Temporary"}) {
+			t.Error("bad types of cyclic error:", types)
+		}
+
+		if tags := Tags(WithTags(j, T("A", "1"))); !equalTags(tags, []Tag{{"A", "1"}}) {
+			t.Error("bad tags of cyclic error:", tags)
+		}
+
+		if msgs, _, _, _, _ := Inspect(WithMessage(a, "hello")); !reflect.DeepEqual(msgs, []string{"hello", "A", "B"}) {
+			t.Error("bad messages of cyclic error:", msgs)
+		}
+
+		if s := fmt.Sprintf("%v", WithMessage(j, "hello")); s != "hello\n└── C" {
+			t.Errorf("bad tree representation of cyclic error: %q", s)
+		}
+
+		if s := fmt.Sprintf("% v", WithMessage(j, "hello")); s != "hello <- C" {
+			t.Errorf("bad single line representation of cyclic error: %q", s)
+		}
+
+		if s := Oneline(j); s != ". <- C" {
+			t.Errorf("bad single line representation of cyclic error: %q", s)
+		}
+
+		if v := ValueOf(j); len(v.Causes) != 1 || v.Causes[0].Message != "C" {
+			t.Error("bad value of cyclic error:", v)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout traversing cyclic errors")
+	}
+}
+
+type errorWithCycle struct {
+	msg   string
+	cause error
+}
+
+func (e *errorWithCycle) Error() string   { return e.msg }
+func (e *errorWithCycle) Message() string { return e.msg }
+func (e *errorWithCycle) Cause() error    { return e.cause }
+
+type errorsWithCycle struct {
+	causes []error
+}
+
+func (e *errorsWithCycle) Error() string   { return "cycle" }
+func (e *errorsWithCycle) Causes() []error { return e.causes }
+
+func TestSafeGo(t *testing.T) {
+	results := make(chan error)
+	ch := make(chan error)
+
+	tasks := []func() error{
+		func() error { return nil },
+		func() error { return New("A") },
+		func() error { panic("B") },
+	}
+
+	for _, task := range tasks {
+		SafeGo(results, task)
+	}
+
+	go func() {
+		for range tasks {
+			ch <- <-results
+		}
+		close(ch)
+	}()
+
+	err := Recv(ch)
+	causes := Causes(err)
+
+	if len(causes) != 2 {
+		t.Fatal("bad number of causes:", causes)
+	}
+
+	var panicked error
+	for _, cause := range causes {
+		if Is("Panic", cause) {
+			panicked = cause
+		}
+	}
+
+	if panicked == nil {
+		t.Fatal("the recovered panic is missing from the combined error:", err)
+	}
+
+	if s := panicked.Error(); s != "panic: B" {
+		t.Error("bad error message of the recovered panic:", s)
+	}
+
+	if _, _, _, stacks, _ := Inspect(panicked); len(stacks) == 0 {
+		t.Error("the recovered panic has no stack trace")
+	}
+}
+
+func TestWalk(t *testing.T) {
+	a := New("A")
+	b := WithTypes(New("B"), "Timeout")
+	c := New("C")
+	j := Join(a, b, c)
+	err := WithMessage(j, "hello")
+
+	t.Run("full traversal", func(t *testing.T) {
+		var found []error
+		Walk(err, func(e error) bool {
+			found = append(found, e)
+			return true
+		})
+
+		expected := []error{err, j, a, b, Cause(b), c}
+
+		if !reflect.DeepEqual(found, expected) {
+			t.Error("bad traversal:")
+			t.Logf("expected: %v", expected)
+			t.Logf("found:    %v", found)
+		}
+	})
+
+	t.Run("early termination", func(t *testing.T) {
+		var found []error
+		Walk(err, func(e error) bool {
+			found = append(found, e)
+			return e != j
+		})
+
+		expected := []error{err, j}
+
+		if !reflect.DeepEqual(found, expected) {
+			t.Error("bad traversal:")
+			t.Logf("expected: %v", expected)
+			t.Logf("found:    %v", found)
+		}
+	})
+
+	t.Run("pruned subtree", func(t *testing.T) {
+		var found []error
+		Walk(err, func(e error) bool {
+			found = append(found, e)
+			return e != b
+		})
+
+		expected := []error{err, j, a, b, c}
+
+		if !reflect.DeepEqual(found, expected) {
+			t.Error("bad traversal:")
+			t.Logf("expected: %v", expected)
+			t.Logf("found:    %v", found)
+		}
+	})
+
+	t.Run("nil error", func(t *testing.T) {
+		Walk(nil, func(e error) bool {
+			t.Error("visiting a nil error:", e)
+			return true
+		})
+	})
+}
+
+func TestTypeHistogram(t *testing.T) {
+	tests := []struct {
+		scenario  string
+		err       error
+		histogram map[string]int
+	}{
+		{
+			scenario: "nil errors have no histogram",
+		},
+
+		{
+			scenario:  "errors with no types have an empty histogram",
+			err:       New("A"),
+			histogram: map[string]int{},
+		},
+
+		{
+			scenario: "leaf errors of a join are counted by type",
+			err: Join(
+				WithTypes(New("A"), "Timeout"),
+				WithTypes(New("B"), "Timeout", "Temporary"),
+				New("C"),
+				&timeout{},
+				WithTypes(Join(New("D"), New("E")), "Throttled"),
+			),
+			histogram: map[string]int{
+				"Temporary": 2,
+				"Throttled": 2,
+				"Timeout":   3,
+			},
+		},
+
+		{
+			scenario: "types of parent errors are inherited by leaf errors",
+			err: WithTypes(Join(
+				WithTypes(New("A"), "Timeout", "Batch"),
+				New("B"),
+			), "Batch"),
+			histogram: map[string]int{
+				"Batch":   2,
+				"Timeout": 1,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			if h := TypeHistogram(test.err); !reflect.DeepEqual(h, test.histogram) {
+				t.Error("bad type histogram:")
+				t.Logf("expected: %v", test.histogram)
+				t.Logf("found:    %v", h)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	domain := &domainError{}
+	typed := WithTypes(New("B"), "Throttled")
+
+	err := Wrap(Join(
+		New("A"),
+		WithMessage(typed, "hello"),
+		Join(New("C"), domain),
+	), "world")
+
+	if found := Find(err, func(e error) bool {
+		_, ok := e.(*domainError)
+		return ok
+	}); found != domain {
+		t.Error("bad error found by predicate:", found)
+	}
+
+	if found := Find(err, func(error) bool { return false }); found != nil {
+		t.Error("unexpected error found by predicate:", found)
+	}
+
+	if found := Find(nil, func(error) bool { return true }); found != nil {
+		t.Error("unexpected error found in nil error:", found)
+	}
+
+	if found := FindType(err, "Throttled"); found != typed {
+		t.Error("bad error found by type:", found)
+	}
+
+	if found := FindType(err, "Timeout"); found != nil {
+		t.Error("unexpected error found by type:", found)
+	}
+
+	if found := FindType(Join(New("A"), &timeout{}), "Timeout"); found == nil {
+		t.Error("errors implementing types with methods must be found by type")
+	}
+}
+
+type domainError struct{}
+
+func (*domainError) Error() string { return "domain" }
+
+func TestWrapDistinct(t *testing.T) {
+	if err := WrapDistinct(nil, "not found"); err != nil {
+		t.Error("wrapping a nil error must return nil:", err)
+	}
+
+	tests := []struct {
+		scenario string
+		err      error
+		msg      string
+		string   string
+	}{
+		{
+			scenario: "wrapping with an identical message does not repeat it",
+			err:      New("not found"),
+			msg:      "not found",
+			string:   "not found",
+		},
+
+		{
+			scenario: "wrapping a wrapped error with an identical message does not repeat it",
+			err:      Wrap(New("no such key"), "not found"),
+			msg:      "not found",
+			string:   "not found: no such key",
+		},
+
+		{
+			scenario: "wrapping a foreign error with an identical message does not repeat it",
+			err:      errors.New("not found"),
+			msg:      "not found",
+			string:   "not found",
+		},
+
+		{
+			scenario: "wrapping with a new message adds a layer",
+			err:      New("no such key"),
+			msg:      "not found",
+			string:   "not found: no such key",
+		},
+
+		{
+			scenario: "wrapping a join with a message adds a layer",
+			err:      Join(New("not found"), New("not found")),
+			msg:      "not found",
+			string:   "not found: not found; not found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			err := WrapDistinct(test.err, test.msg)
+
+			if s := err.Error(); s != test.string {
+				t.Error("bad error message:")
+				t.Logf("expected: %s", test.string)
+				t.Logf("found:    %s", s)
+			}
+
+			if _, _, _, stacks, _ := Inspect(err); len(stacks) == 0 {
+				t.Error("the wrapped error has no stack trace")
+			}
+		})
+	}
+}
+
+func TestExtract(t *testing.T) {
+	domain := &domainError{}
+
+	err := Wrap(Join(
+		New("A"),
+		WithMessage(&timeout{}, "B"),
+		Join(New("C"), domain),
+	), "hello")
+
+	t.Run("concrete type inside a join", func(t *testing.T) {
+		var e *domainError
+
+		if !Extract(err, &e) {
+			t.Fatal("the error was not extracted")
+		}
+
+		if e != domain {
+			t.Error("bad extracted error:", e)
+		}
+	})
+
+	t.Run("interface type inside a join", func(t *testing.T) {
+		var e interface {
+			Timeout() bool
+		}
+
+		if !Extract(err, &e) {
+			t.Fatal("the error was not extracted")
+		}
+
+		if _, ok := e.(*timeout); !ok {
+			t.Errorf("bad extracted error: %T", e)
+		}
+	})
+
+	t.Run("missing type", func(t *testing.T) {
+		var e *errorWithNilCause
+
+		if Extract(err, &e) {
+			t.Error("unexpected extracted error:", e)
+		}
+
+		if Extract(nil, &e) {
+			t.Error("unexpected extracted error from nil:", e)
+		}
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("extracting to a non-pointer target must panic")
+			}
+		}()
+		Extract(err, domainError{})
+	})
+}
+
+func TestTagsMap(t *testing.T) {
+	tests := []struct {
+		scenario string
+		err      error
+		tags     map[string][]string
+	}{
+		{
+			scenario: "nil errors have no tags",
+		},
+
+		{
+			scenario: "errors with no tags return a nil map",
+			err:      New("hello"),
+		},
+
+		{
+			scenario: "repeated keys at different nesting levels are ordered outermost first",
+			err: WithTags(
+				Wrap(
+					Join(
+						WithTags(New("A"), T("key", "inner-1"), T("A", "1")),
+						WithTags(New("B"), T("key", "inner-2")),
+					),
+					"hello",
+				),
+				T("key", "outer"),
+				T("env", "production"),
+			),
+			tags: map[string][]string{
+				"A":   {"1"},
+				"env": {"production"},
+				"key": {"outer", "inner-1", "inner-2"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			if tags := TagsMap(test.err); !reflect.DeepEqual(tags, test.tags) {
+				t.Error("bad tags map:")
+				t.Logf("expected: %v", test.tags)
+				t.Logf("found:    %v", tags)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if err := WithRetryAfter(nil, time.Second); err != nil {
+		t.Error("attaching a retry-after duration to a nil error must return nil:", err)
+	}
+
+	if d, ok := RetryAfter(New("hello")); ok || d != 0 {
+		t.Error("unexpected retry-after duration:", d)
+	}
+
+	err := Wrap(WithRetryAfter(New("throttled"), 30*time.Second), "hello")
+
+	if d, ok := RetryAfter(err); !ok || d != 30*time.Second {
+		t.Error("bad retry-after duration:", d, ok)
+	}
+
+	if d, ok := RetryAfter(Join(New("A"), err)); !ok || d != 30*time.Second {
+		t.Error("bad retry-after duration of a join:", d, ok)
+	}
+
+	val := ValueOf(err)
+
+	if val.RetryAfter != 30*time.Second {
+		t.Error("bad retry-after duration in the error value:", val.RetryAfter)
+	}
+
+	if d, ok := RetryAfter(val.Err()); !ok || d != 30*time.Second {
+		t.Error("bad retry-after duration of the error rebuilt from a value:", d, ok)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC)
+	SetClock(func() time.Time { return now })
+	defer SetClock(nil)
+
+	tests := []struct {
+		value      string
+		retryAfter time.Duration
+	}{
+		{"", 0},
+		{"0", 0},
+		{"-1", 0},
+		{"120", 2 * time.Minute},
+		{"Thu, 02 Jan 2020 03:05:05 GMT", time.Minute},
+		{"Thursday, 02-Jan-20 03:05:05 GMT", time.Minute},
+		{"Thu Jan  2 03:05:05 2020", time.Minute},
+		{"Thu, 02 Jan 2020 03:03:05 GMT", 0},
+		{"soon", 0},
+	}
+
+	for _, test := range tests {
+		if d := ParseRetryAfter(test.value); d != test.retryAfter {
+			t.Errorf("bad retry-after duration for %q: %v", test.value, d)
+		}
+	}
+}
+
+func TestWithTagsMap(t *testing.T) {
+	if err := WithTagsMap(nil, map[string]string{"A": "1"}); err != nil {
+		t.Error("tagging a nil error must return nil:", err)
+	}
+
+	tests := []struct {
+		scenario string
+		tags     map[string]string
+		expected []Tag
+	}{
+		{
+			scenario: "nil map",
+		},
+
+		{
+			scenario: "empty map",
+			tags:     map[string]string{},
+		},
+
+		{
+			scenario: "tags are sorted by name",
+			tags:     map[string]string{"C": "3", "A": "1", "B": "2"},
+			expected: []Tag{{"A", "1"}, {"B", "2"}, {"C", "3"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			err := WithTagsMap(New("hello"), test.tags)
+
+			if tags := Tags(err); !equalTags(tags, test.expected) {
+				t.Error("bad error tags:", tags, "!=", test.expected)
+			}
+
+			for _, tag := range test.expected {
+				if value := LookupTag(err, tag.Name); value != tag.Value {
+					t.Errorf("bad value of tag %q: %q", tag.Name, value)
+				}
+			}
+		})
+	}
+}
+
+func TestMetricTags(t *testing.T) {
+	RegisterHighCardinalityTags("test_user_id", "test_request_id")
+
+	err := WithTags(
+		Wrap(WithTags(New("hello"), T("test_user_id", "1234"), T("env", "production")), "world"),
+		T("test_request_id", "abcd"),
+		T("operation", "seek"),
+	)
+
+	if !IsHighCardinalityTag("test_user_id") || IsHighCardinalityTag("env") {
+		t.Error("bad high-cardinality tag registry")
+	}
+
+	if tags := Tags(err); !equalTags(tags, []Tag{
+		{"env", "production"},
+		{"operation", "seek"},
+		{"test_request_id", "abcd"},
+		{"test_user_id", "1234"},
+	}) {
+		t.Error("high-cardinality tags must be part of the error tags:", tags)
+	}
+
+	if tags := MetricTags(err); !equalTags(tags, []Tag{
+		{"env", "production"},
+		{"operation", "seek"},
+	}) {
+		t.Error("high-cardinality tags must be excluded from the metric tags:", tags)
+	}
+
+	if tags := MetricTags(WithTags(New("hello"), T("test_user_id", "1234"))); tags != nil {
+		t.Error("unexpected metric tags:", tags)
+	}
+}
+
+func TestDistinctTags(t *testing.T) {
+	err := WithTags(
+		Wrap(
+			Join(
+				WithTags(New("A"), T("key", "inner-1"), T("A", "1")),
+				WithTags(New("B"), T("key", "inner-2")),
+			),
+			"hello",
+		),
+		T("key", "outer"),
+	)
+
+	if tags := Tags(err); !equalTags(tags, []Tag{
+		{"A", "1"},
+		{"key", "inner-1"},
+		{"key", "inner-2"},
+		{"key", "outer"},
+	}) {
+		t.Error("bad tags:", tags)
+	}
+
+	if tags := DistinctTags(err); !equalTags(tags, []Tag{
+		{"A", "1"},
+		{"key", "outer"},
+	}) {
+		t.Error("bad distinct tags:", tags)
+	}
+
+	if tags := DistinctTags(nil); tags != nil {
+		t.Error("unexpected distinct tags of nil error:", tags)
+	}
+}
+
+func TestFromTypes(t *testing.T) {
+	err := FromTypes("request timed out", "Timeout", "Temporary")
+
+	if s := err.Error(); s != "request timed out" {
+		t.Error("bad error message:", s)
+	}
+
+	if types := Types(err); !equalTypes(types, []string{"Temporary", "Timeout"}) {
+		t.Error("bad error types:", types)
+	}
+
+	if !Is("Timeout", err) || !Is("Temporary", err) || Is("Throttled", err) {
+		t.Error("bad error types reported by Is")
+	}
+
+	if _, _, _, stacks, _ := Inspect(err); len(stacks) == 0 {
+		t.Error("the error has no stack trace")
+	}
+
+	if types := Types(FromTypes("hello")); types != nil {
+		t.Error("unexpected error types:", types)
+	}
+}
+
+func TestTagConstructors(t *testing.T) {
+	tests := []struct {
+		tag   Tag
+		value string
+	}{
+		{TInt("status", 503), "503"},
+		{TInt("offset", -42), "-42"},
+		{TBool("retry", true), "true"},
+		{TBool("retry", false), "false"},
+		{TFloat("ratio", 0.5), "0.5"},
+		{TFloat("ratio", 1e21), "1e+21"},
+		{TFloat("ratio", 3), "3"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if test.tag.Value != test.value {
+				t.Errorf("bad value of tag %q: %q != %q", test.tag.Name, test.tag.Value, test.value)
+			}
+		})
+	}
+}
+
+func TestConcreteTypes(t *testing.T) {
+	err := Join(
+		WithTypes(New("A"), "ServiceUnavailable", "Temporary"),
+		&timeout{},
+	)
+
+	if types := ConcreteTypes(err); !equalTypes(types, []string{"ServiceUnavailable"}) {
+		t.Error("bad concrete types:", types)
+	}
+
+	if types := ConcreteTypes(err, "ServiceUnavailable"); !equalTypes(types, []string{"Temporary", "Timeout"}) {
+		t.Error("bad concrete types:", types)
+	}
+
+	if types := ConcreteTypes(&timeout{}); types != nil {
+		t.Error("unexpected concrete types:", types)
+	}
+}
+
+func TestHasAnyStack(t *testing.T) {
+	tests := []struct {
+		scenario string
+		err      error
+		stack    bool
+	}{
+		{
+			scenario: "nil error",
+		},
+
+		{
+			scenario: "stackless foreign error",
+			err:      errors.New("hello"),
+		},
+
+		{
+			scenario: "stackless error with types and tags",
+			err:      WithTags(WithTypes(errors.New("hello"), "Timeout"), T("A", "1")),
+		},
+
+		{
+			scenario: "wrapped foreign error",
+			err:      Wrap(errors.New("hello"), "world"),
+			stack:    true,
+		},
+
+		{
+			scenario: "join with a stack in one branch",
+			err:      Join(errors.New("A"), WithTypes(New("B"), "Timeout")),
+			stack:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			if stack := HasAnyStack(test.err); stack != test.stack {
+				t.Errorf("bad result: %t != %t", stack, test.stack)
+			}
+		})
+	}
+}
+
+func TestPanickingTypeMethod(t *testing.T) {
+	err := WithTypes(&panickingError{}, "Throttled")
+
+	if types := Types(err); !equalTypes(types, []string{"Temporary", "Throttled"}) {
+		t.Error("bad error types:", types)
+	}
+
+	if Is("Closed", err) {
+		t.Error("errors with panicking methods must not be of the method type")
+	}
+
+	if !Is("Temporary", err) {
+		t.Error("the error was expected to be temporary")
+	}
+}
+
+type panickingError struct{}
+
+func (*panickingError) Error() string   { return "panicking" }
+func (*panickingError) Closed() bool    { panic("boom") }
+func (*panickingError) Temporary() bool { return true }
+
+func TestRecvTimeout(t *testing.T) {
+	t.Run("the channel is closed before the timeout", func(t *testing.T) {
+		err := RecvTimeout(errorChan(nil, New("A"), &timeout{}), time.Minute)
+
+		if causes := Causes(err); len(causes) != 2 {
+			t.Error("bad causes:", causes)
+		}
+
+		if Is("RecvTimeout", err) {
+			t.Error("unexpected receive timeout:", err)
+		}
+
+		if err := RecvTimeout(errorChan(nil), time.Minute); err != nil {
+			t.Error("unexpected error:", err)
+		}
+	})
+
+	t.Run("the channel never closes", func(t *testing.T) {
+		ch := make(chan error, 2)
+		ch <- New("A")
+		ch <- &timeout{}
+
+		err := RecvTimeout(ch, 10*time.Millisecond)
+		causes := Causes(err)
+
+		if len(causes) != 3 {
+			t.Fatal("bad causes:", causes)
+		}
+
+		if Is("RecvTimeout", causes[0]) || Is("RecvTimeout", causes[1]) {
+			t.Error("errors reported on the channel must not be receive timeouts")
+		}
+
+		if !Is("RecvTimeout", causes[2]) || !Is("Timeout", causes[2]) {
+			t.Error("the last cause must be a receive timeout:", causes[2])
+		}
+	})
+}
+
+func TestRecvFirst(t *testing.T) {
+	t.Run("the channel is closed before any error", func(t *testing.T) {
+		if err := RecvFirst(errorChan(nil, nil)); err != nil {
+			t.Error("unexpected error:", err)
+		}
+	})
+
+	t.Run("the remaining errors are drained", func(t *testing.T) {
+		ch := make(chan error)
+		wg := sync.WaitGroup{}
+
+		for i := 0; i != 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if i == 0 {
+					ch <- New("A")
+				} else {
+					time.Sleep(time.Millisecond)
+					ch <- fmt.Errorf("error %d", i)
+				}
+			}(i)
+		}
+
+		go func() { wg.Wait(); close(ch) }()
+
+		if err := RecvFirst(ch); err == nil || err.Error() != "A" {
+			t.Error("bad error:", err)
+		}
+
+		done := make(chan struct{})
+		go func() { wg.Wait(); close(done) }()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("the senders are blocked, the channel was not drained")
+		}
+	})
+}
+
+func TestTypesCache(t *testing.T) {
+	t.Run("internal errors", func(t *testing.T) {
+		err := Wrap(Join(WithTypes(New("A"), "Timeout"), WithTypes(New("B"), "Temporary")), "hello")
+
+		for i := 0; i != 2; i++ {
+			if types := Types(err); !equalTypes(types, []string{"Temporary", "Timeout"}) {
+				t.Error("bad error types:", types)
+			}
+			if !Is("Timeout", err) || Is("Throttled", err) {
+				t.Error("bad error types reported by Is")
+			}
+		}
+
+		if types, ok := cachedTypes(err); !ok || !equalTypes(types, []string{"Temporary", "Timeout"}) {
+			t.Error("the types of internal errors must be cached:", types)
+		}
+
+		// mutating the returned slice must not alter the cache
+		Types(err)[0] = "Throttled"
+
+		if Is("Throttled", err) {
+			t.Error("the cached types were mutated")
+		}
+	})
+
+	t.Run("foreign errors", func(t *testing.T) {
+		e := &mutableError{}
+		err := Wrap(e, "hello")
+
+		if Is("Timeout", err) {
+			t.Error("the error was not expected to be a timeout")
+		}
+
+		e.timeout = true
+
+		if !Is("Timeout", err) {
+			t.Error("the types of errors with foreign causes must not be cached")
+		}
+
+		if _, ok := cachedTypes(err); ok {
+			t.Error("the types of errors with foreign causes must not be cached")
+		}
+	})
+}
+
+type mutableError struct{ timeout bool }
+
+func (e *mutableError) Error() string { return "mutable" }
+func (e *mutableError) Timeout() bool { return e.timeout }
+
+func BenchmarkIs(b *testing.B) {
+	err := Wrap(Join(WithTypes(New("A"), "Timeout"), WithTypes(New("B"), "Temporary")), "hello")
+
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Is("Temporary", err)
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			is("Temporary", err, nil)
+		}
+	})
+}
+
+func BenchmarkTypeMethod(b *testing.B) {
+	err := &timeout{}
+
+	b.Run("asserted", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			typeMethod("Timeout", err)()
+		}
+	})
+
+	b.Run("reflective", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reflectTypeMethod("Timeout", err)()
+		}
+	})
+}
+
+func TestErrWithStack(t *testing.T) {
+	tests := []struct {
+		scenario string
+		value    interface{}
+	}{
+		{"string", "boom"},
+		{"foreign error", errors.New("boom")},
+		{"arbitrary value", 42},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			err := recoverWithStack(test.value)
+
+			if err == nil {
+				t.Fatal("no error returned after recovering from a panic")
+			}
+
+			_, _, _, stacks, _ := Inspect(err)
+
+			if len(stacks) == 0 {
+				t.Fatal("the error has no stack trace")
+			}
+
+			found := false
+
+			for _, frame := range stacks[0] {
+				if strings.HasSuffix(fmt.Sprintf("%n", frame), "panicWith") {
+					found = true
+				}
+			}
+
+			if !found {
+				t.Errorf("the stack trace does not contain the panicking function:\n%+v", stacks[0])
+			}
+		})
+	}
+
+	if err := ErrWithStack(nil); err != nil {
+		t.Error("unexpected error:", err)
+	}
+
+	if err := New("A"); ErrWithStack(err) != err {
+		t.Error("errors carrying a stack trace must be returned unchanged")
+	}
+}
+
+func recoverWithStack(v interface{}) (err error) {
+	defer func() { err = ErrWithStack(recover()) }()
+	panicWith(v)
+	return nil
+}
+
+//go:noinline
+func panicWith(v interface{}) {
+	panic(v)
+}
+
+func TestNotImplemented(t *testing.T) {
+	err := NotImplemented("batch uploads")
+
+	if s := err.Error(); s != "batch uploads is not implemented" {
+		t.Error("bad error message:", s)
+	}
+
+	if !Is("NotImplemented", Wrap(err, "hello")) {
+		t.Error("the error was expected to be of type NotImplemented")
+	}
+
+	if _, _, _, stacks, _ := Inspect(err); len(stacks) == 0 {
+		t.Error("the error has no stack trace")
+	}
+}
+
+func TestIgnore(t *testing.T) {
+	notFound := WithTypes(New("no such key"), "NotFound")
+	other := New("hello")
+
+	if err := Ignore(Wrap(notFound, "delete"), "Timeout", "NotFound"); err != nil {
+		t.Error("errors of an ignored type must be discarded:", err)
+	}
+
+	if err := Ignore(other, "NotFound"); err != other {
+		t.Error("errors of other types must be returned unchanged:", err)
+	}
+
+	if err := Ignore(notFound); err != notFound {
+		t.Error("errors must be returned unchanged when no types are ignored:", err)
+	}
+
+	if err := Ignore(nil, "NotFound"); err != nil {
+		t.Error("nil errors must be returned as nil:", err)
+	}
+}
+
+func TestAppendTags(t *testing.T) {
+	base := New("hello")
+
+	composed := WithTags(WithTags(WithTags(base, T("A", "1"), T("key", "inner")), T("B", "2")), T("key", "outer"))
+	appended := AppendTags(AppendTags(WithTags(base, T("A", "1"), T("key", "inner")), T("B", "2")), T("key", "outer"))
+
+	if c := Cause(appended); c != base {
+		t.Error("appending tags must not add wrappers:", c)
+	}
+
+	if _, ok := appended.(*errorWithTags); !ok || Causes(appended)[0] != base {
+		t.Errorf("bad structure of the error with appended tags: %#v", appended)
+	}
+
+	if t1, t2 := Tags(composed), Tags(appended); !equalTags(t1, t2) {
+		t.Error("tags mismatch:", t1, "!=", t2)
+	}
+
+	if t1, t2 := DistinctTags(composed), DistinctTags(appended); !equalTags(t1, t2) {
+		t.Error("distinct tags mismatch:", t1, "!=", t2)
+	}
+
+	if t1, t2 := TagsMap(composed), TagsMap(appended); !reflect.DeepEqual(t1, t2) {
+		t.Error("tags map mismatch:", t1, "!=", t2)
+	}
+
+	if t1, t2 := ValueOf(composed).Tags, ValueOf(appended).Tags; !reflect.DeepEqual(t1, t2) {
+		t.Error("value tags mismatch:", t1, "!=", t2)
+	}
+
+	if err := AppendTags(nil, T("A", "1")); err != nil {
+		t.Error("appending tags to a nil error must return nil:", err)
+	}
+
+	if tags := Tags(AppendTags(base, T("A", "1"))); !equalTags(tags, []Tag{{"A", "1"}}) {
+		t.Error("bad tags:", tags)
+	}
+}
+
+func BenchmarkAppendTags(b *testing.B) {
+	base := New("hello")
+
+	b.Run("AppendTags", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			err := base
+			for j := 0; j != 10; j++ {
+				err = AppendTags(err, T("key", "value"))
+			}
+			Tags(err)
+		}
+	})
+
+	b.Run("WithTags", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			err := base
+			for j := 0; j != 10; j++ {
+				err = WithTags(err, T("key", "value"))
+			}
+			Tags(err)
+		}
+	})
+}
+
+func TestGroupByType(t *testing.T) {
+	err := Join(
+		WithTypes(New("A"), "RequestTimeout", "Timeout", "Temporary"),
+		WithTypes(New("B"), "RequestTimeout", "Timeout", "Temporary"),
+		WithTypes(New("C"), "NotFound"),
+		&timeout{},
+		New("D"),
+		WithTypes(Join(New("E"), New("F")), "Internal"),
+	)
+
+	expected := map[string]int{
+		"":               1,
+		"Internal":       2,
+		"NotFound":       1,
+		"RequestTimeout": 2,
+		"Temporary":      1,
+	}
+
+	if g := GroupByType(err); !reflect.DeepEqual(g, expected) {
+		t.Error("bad grouping by type:")
+		t.Logf("expected: %v", expected)
+		t.Logf("found:    %v", g)
+	}
+
+	if g := GroupByType(nil); g != nil {
+		t.Error("unexpected grouping of nil error:", g)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	base := New("oops")
+	stack := CaptureStackTrace(0)
+
+	err := WithTypes(WithTypes(WithStackTrace(WithStackTrace(base, stack), stack), "A", "B"), "A", "B")
+	err = WithTags(Wrap(err, "context"), T("a", "1"))
+	err = Join(err, WithStack(WithStack(New("other"))))
+
+	norm := Normalize(err)
+
+	if norm.Error() != err.Error() {
+		t.Errorf("bad message: %q != %q", norm.Error(), err.Error())
+	}
+
+	if !reflect.DeepEqual(Types(norm), Types(err)) {
+		t.Errorf("bad types: %q != %q", Types(norm), Types(err))
+	}
+
+	if !reflect.DeepEqual(Tags(norm), Tags(err)) {
+		t.Errorf("bad tags: %v != %v", Tags(norm), Tags(err))
+	}
+
+	causes := Causes(norm)
+	if len(causes) != 2 {
+		t.Fatalf("bad number of causes: %d", len(causes))
+	}
+
+	if Cause(causes[0]) != base {
+		t.Error("the root cause was not preserved")
+	}
+
+	depth := func(err error) (n int) {
+		for e, ok := err.(errorCause); ok; e, ok = e.Cause().(errorCause) {
+			n++
+		}
+		return
+	}
+
+	// tags, message, stack (from Wrap), types, stack
+	if n := depth(causes[0]); n != 5 {
+		t.Errorf("bad depth of the first normalized cause: %d", n)
+	}
+
+	// stack
+	if n := depth(causes[1]); n != 1 {
+		t.Errorf("bad depth of the second normalized cause: %d", n)
+	}
+
+	if Normalize(nil) != nil {
+		t.Error("normalizing nil must return nil")
+	}
+}
+
+func TestNewNoStack(t *testing.T) {
+	for _, err := range []error{
+		NewNoStack("answer: 42"),
+		ErrorfNoStack("answer: %d", 42),
+	} {
+		if s := err.Error(); s != "answer: 42" {
+			t.Error("bad error message:", s)
+		}
+
+		if HasAnyStack(err) {
+			t.Error("unexpected stack trace on error:", stackTrace(err))
+		}
+	}
+}
+
+var benchmarkErr error
+
+func BenchmarkNew(b *testing.B) {
+	b.Run("New", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			benchmarkErr = New("oops")
+		}
+	})
+
+	b.Run("NewNoStack", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			benchmarkErr = NewNoStack("oops")
+		}
+	})
+}
+
+func TestWithContext(t *testing.T) {
+	type request struct{ id int }
+
+	if WithContext(nil, "key", 1) != nil {
+		t.Error("adding context to a nil error must return nil")
+	}
+
+	base := New("oops")
+	err := WithContext(base, "request", &request{id: 42})
+	err = Wrap(WithContext(err, "attempt", 3), "retries exhausted")
+	err = WithTypes(WithContext(err, "attempt", 4), "Timeout")
+
+	if v, ok := Context(err, "request"); !ok || v.(*request).id != 42 {
+		t.Error("bad request context:", v, ok)
+	}
+
+	if v, ok := Context(err, "attempt"); !ok || v != 4 {
+		t.Error("the outermost context value must be returned:", v, ok)
+	}
+
+	if v, ok := Context(err, "missing"); ok || v != nil {
+		t.Error("unexpected context value:", v, ok)
+	}
+
+	if v, ok := Context(Join(New("A"), err), "request"); !ok || v.(*request).id != 42 {
+		t.Error("the context was not found through multiple causes:", v, ok)
+	}
+
+	if Cause(err) != base {
+		t.Error("context values must not alter the cause of errors")
+	}
+
+	if s := err.Error(); s != "retries exhausted: oops" {
+		t.Error("bad error message:", s)
+	}
+}
+
+func TestCauseOfType(t *testing.T) {
+	base := &timeout{}
+	typed := WithTypes(WithMessage(base, "request failed"), "Unavailable")
+	err := Wrap(WithTags(typed, T("user", "42")), "cannot load user")
+
+	if found := CauseOfType(err, "Unavailable"); found != typed {
+		t.Error("bad error found for the Unavailable type:", found)
+	}
+
+	if found := CauseOfType(err, "Timeout"); found != base {
+		t.Error("bad error found for the Timeout type:", found)
+	}
+
+	if found := CauseOfType(err, "Throttled"); found != nil {
+		t.Error("unexpected error found for the Throttled type:", found)
+	}
+
+	if found := CauseOfType(Join(New("A"), typed), "Unavailable"); found != typed {
+		t.Error("bad error found in errors with multiple causes:", found)
+	}
+
+	// The outermost error defining the type decides, the same way as Is.
+	shadowed := Wrap(&notTimeout{cause: base}, "shadowed")
+
+	if found := CauseOfType(shadowed, "Timeout"); found != nil {
+		t.Error("unexpected error found behind an error defining the type:", found)
+	}
+
+	if found := FindType(shadowed, "Timeout"); found != base {
+		t.Error("FindType must consider all errors of the graph:", found)
+	}
+
+	if found := CauseOfType(nil, "Timeout"); found != nil {
+		t.Error("unexpected error found in nil error:", found)
+	}
+}
+
+type notTimeout struct{ cause error }
+
+func (e *notTimeout) Error() string { return "not timeout: " + e.cause.Error() }
+func (e *notTimeout) Cause() error  { return e.cause }
+func (e *notTimeout) Timeout() bool { return false }
+
+func TestRange(t *testing.T) {
+	a, b, c := New("A"), New("B"), New("C")
+	err := Wrap(Join(a, b, c), "batch failed")
+
+	t.Run("full iteration", func(t *testing.T) {
+		var found []error
+		Range(err, func(cause error) bool {
+			found = append(found, cause)
+			return true
+		})
+
+		if !reflect.DeepEqual(found, Causes(err)) {
+			t.Error("causes mismatch:", found)
+		}
+	})
+
+	t.Run("early stop", func(t *testing.T) {
+		var found []error
+		Range(err, func(cause error) bool {
+			found = append(found, cause)
+			return cause != b
+		})
+
+		if len(found) != 2 || found[0] != a || found[1] != b {
+			t.Error("the iteration did not stop early:", found)
+		}
+	})
+
+	t.Run("single cause", func(t *testing.T) {
+		var found []error
+		Range(Wrap(a, "context"), func(cause error) bool {
+			found = append(found, cause)
+			return true
+		})
+
+		if len(found) != 1 || found[0] != Cause(a) {
+			t.Error("bad causes of an error with a single cause:", found)
+		}
+	})
+
+	t.Run("no causes", func(t *testing.T) {
+		Range(nil, func(error) bool {
+			t.Error("the function must not be called on a nil error")
+			return true
+		})
+	})
+}
+
+func TestAllCausesHaveType(t *testing.T) {
+	mixed := Join(&timeout{}, New("A"), &timeout{})
+	all := Wrap(Join(&timeout{}, WithMessage(&timeout{}, "B"), Join(&timeout{}, &timeout{})), "batch failed")
+
+	if !Is("Timeout", mixed) {
+		t.Error("Is must return true when any of the causes has the type")
+	}
+
+	if AllCausesHaveType(mixed, "Timeout") {
+		t.Error("AllCausesHaveType must return false when some causes do not have the type")
+	}
+
+	if !AllCausesHaveType(all, "Timeout") {
+		t.Error("AllCausesHaveType must return true when all causes have the type")
+	}
+
+	if AllCausesHaveType(Join(&timeout{}, Join(&timeout{}, New("C"))), "Timeout") {
+		t.Error("AllCausesHaveType must test the nested causes")
+	}
+
+	if !AllCausesHaveType(WithTypes(mixed, "Timeout"), "Timeout") {
+		t.Error("the types of errors wrapping the causes must decide the result")
+	}
+
+	if !AllCausesHaveType(Wrap(&timeout{}, "single"), "Timeout") {
+		t.Error("AllCausesHaveType must behave like Is on errors with a single cause")
+	}
+
+	if AllCausesHaveType(nil, "Timeout") {
+		t.Error("AllCausesHaveType must return false on nil errors")
+	}
+}
+
+func TestWithoutTags(t *testing.T) {
+	base := WithTags(New("A"), T("email", "me@example.com"), T("user", "42"))
+	err := WithTags(Join(base, WithTags(New("B"), T("email", "you@example.com"))), T("env", "prod"))
+	masked := WithoutTags(err, "email")
+
+	expected := []Tag{T("env", "prod"), T("user", "42")}
+
+	if tags := Tags(masked); !reflect.DeepEqual(tags, expected) {
+		t.Error("bad tags:", tags)
+	}
+
+	if tags := DistinctTags(masked); !reflect.DeepEqual(tags, expected) {
+		t.Error("bad distinct tags:", tags)
+	}
+
+	if tags := TagsMap(masked); len(tags) != 2 || len(tags["email"]) != 0 {
+		t.Error("bad tags map:", tags)
+	}
+
+	if value := LookupTag(masked, "email"); value != "" {
+		t.Error("masked tag was found:", value)
+	}
+
+	if s := fmt.Sprint(masked); strings.Contains(s, "example.com") || !strings.Contains(s, `user:"42"`) {
+		t.Error("bad error format:", s)
+	}
+
+	if val := ValueOf(masked); strings.Contains(fmt.Sprint(val), "example.com") {
+		t.Error("masked tags found in the error value:", val)
+	}
+
+	if tags := Tags(err); len(tags) != 4 {
+		t.Error("the tags of the wrapped error must not be modified:", tags)
+	}
+
+	if cause := Causes(masked); len(cause) != 2 || cause[0] != base {
+		t.Error("the causes of the masked error must not be modified:", cause)
+	}
+
+	if WithoutTags(nil, "email") != nil {
+		t.Error("masking the tags of a nil error must return nil")
+	}
+}
+
+func TestWithoutTypes(t *testing.T) {
+	err := WithTypes(Join(&timeout{}, WithTypes(New("B"), "Throttled")), "Unavailable")
+	masked := WithoutTypes(err, "Timeout", "Unavailable")
+
+	if types := Types(masked); !reflect.DeepEqual(types, []string{"Temporary", "Throttled"}) {
+		t.Error("bad types:", types)
+	}
+
+	for _, typ := range []string{"Timeout", "Unavailable"} {
+		if Is(typ, masked) {
+			t.Errorf("masked error must not be of type %q", typ)
+		}
+		if found := FindType(masked, typ); found != nil {
+			t.Errorf("masked error must not have a cause of type %q: %v", typ, found)
+		}
+		if found := CauseOfType(masked, typ); found != nil {
+			t.Errorf("masked error must not have a cause of type %q: %v", typ, found)
+		}
+	}
+
+	if !Is("Temporary", masked) || !Is("Throttled", masked) {
+		t.Error("types that are not masked must remain")
+	}
+
+	if !Is("Timeout", WithTypes(masked, "Timeout")) {
+		t.Error("types set on errors wrapping the mask must not be masked")
+	}
+
+	if s := fmt.Sprint(masked); strings.Contains(s, "Timeout") || strings.Contains(s, "Unavailable") {
+		t.Error("bad error format:", s)
+	}
+
+	if h := TypeHistogram(masked); h["Timeout"] != 0 || h["Unavailable"] != 0 || h["Temporary"] != 1 {
+		t.Error("bad type histogram:", h)
+	}
+
+	if !Is("Timeout", err) {
+		t.Error("the types of the wrapped error must not be modified")
+	}
+
+	if WithoutTypes(nil, "Timeout") != nil {
+		t.Error("masking the types of a nil error must return nil")
+	}
+}
+
+func TestWithoutTagsAndTypesReAdded(t *testing.T) {
+	base := WithTypes(WithTags(New("A"), T("email", "old")), "X")
+	masked := WithoutTypes(WithoutTags(base, "email"), "X")
+	err := WithTypes(WithTags(masked, T("email", "new")), "X")
+
+	if _, types, tags, _, _ := Inspect(err); !reflect.DeepEqual(types, []string{"X"}) || !reflect.DeepEqual(tags, []Tag{T("email", "new")}) {
+		t.Error("bad types and tags:", types, tags)
+	}
+
+	if types := Types(err); !reflect.DeepEqual(types, []string{"X"}) {
+		t.Error("bad types:", types)
+	}
+
+	if tags := Tags(err); !reflect.DeepEqual(tags, []Tag{T("email", "new")}) {
+		t.Error("bad tags:", tags)
+	}
+
+	if s := fmt.Sprint(err); s != `A (X) [email:"new"]` {
+		t.Errorf("bad error format: %q", s)
+	}
+
+	if val := ValueOf(err); !reflect.DeepEqual(val.Types, []string{"X"}) || !reflect.DeepEqual(val.Tags, map[string]string{"email": "new"}) {
+		t.Error("bad error value:", val)
+	}
+}
+
+func TestJoinWithTags(t *testing.T) {
+	err := JoinWithTags([]Tag{T("batch-id", "42")},
+		WithTags(New("A"), T("item", "1")),
+		nil,
+		WithTags(New("B"), T("item", "2")),
+	)
+
+	if n := len(Causes(err)); n != 2 {
+		t.Error("bad number of causes of the joined errors:", n)
+	}
+
+	tags := TagsMap(err)
+
+	if v := tags["batch-id"]; !reflect.DeepEqual(v, []string{"42"}) {
+		t.Error("bad group tag:", v)
+	}
+
+	if v := tags["item"]; !reflect.DeepEqual(v, []string{"1", "2"}) {
+		t.Error("bad child tags:", v)
+	}
+
+	if s := Oneline(err); s != `. [batch-id:"42"] <- {A [item:"1"]; B [item:"2"]}` {
+		t.Error("bad error format:", s)
+	}
+
+	if JoinWithTags([]Tag{T("batch-id", "42")}, nil, nil) != nil {
+		t.Error("joining nil errors must return nil")
+	}
+
+	if _, ok := JoinWithTags(nil, New("A"), New("B")).(*multiError); !ok {
+		t.Error("joining errors without tags must behave like Join")
+	}
+}
+
+func TestSetTagNameNormalizer(t *testing.T) {
+	SetTagNameNormalizer(func(name string) string {
+		b := &strings.Builder{}
+		for i, c := range name {
+			switch {
+			case c == '-':
+				c = '_'
+			case c >= 'A' && c <= 'Z':
+				if i != 0 {
+					b.WriteByte('_')
+				}
+				c += 'a' - 'A'
+			}
+			b.WriteRune(c)
+		}
+		return b.String()
+	})
+	defer SetTagNameNormalizer(nil)
+
+	err := WithTags(New("A"), T("requestId", "1"), T("Host", "localhost"))
+	err = WithTagsMap(err, map[string]string{"request-id": "2"})
+	err = AppendTags(err, T("request_id", "3"))
+
+	if tags := Tags(err); !reflect.DeepEqual(tags, []Tag{
+		{Name: "host", Value: "localhost"},
+		{Name: "request_id", Value: "1"},
+		{Name: "request_id", Value: "2"},
+		{Name: "request_id", Value: "3"},
+	}) {
+		t.Error("bad tags:", tags)
+	}
+
+	for _, name := range []string{"requestId", "request-id", "request_id"} {
+		if value := LookupTag(err, name); value != "3" {
+			t.Errorf("bad value of tag %q: %q", name, value)
+		}
+	}
+
+	if tags := Tags(WithoutTags(err, "request-id")); !reflect.DeepEqual(tags, []Tag{
+		{Name: "host", Value: "localhost"},
+	}) {
+		t.Error("bad tags after masking:", tags)
+	}
+
+	SetTagNameNormalizer(nil)
+
+	if tags := Tags(WithTags(New("B"), T("requestId", "1"))); !reflect.DeepEqual(tags, []Tag{
+		{Name: "requestId", Value: "1"},
+	}) {
+		t.Error("tag names must not be normalized when the normalizer is removed:", tags)
+	}
+}
+
+func TestAsNearest(t *testing.T) {
+	deep := &depthError{depth: 3}
+	shallow := &depthError{depth: 2}
+
+	err := Wrap(Join(
+		Join(New("A"), Wrap(deep, "B")),
+		shallow,
+	), "hello")
+
+	t.Run("the shallowest error is selected", func(t *testing.T) {
+		var e *depthError
+
+		if !AsNearest(err, &e) {
+			t.Fatal("the error was not found")
+		}
+
+		if e != shallow {
+			t.Error("the deepest error was selected")
+		}
+
+		if Extract(err, &e); e != deep {
+			t.Error("Extract must walk the graph of causes in depth-first order")
+		}
+	})
+
+	t.Run("interface type", func(t *testing.T) {
+		var e interface {
+			Timeout() bool
+		}
+
+		if !AsNearest(Join(New("A"), Wrap(&timeout{}, "B")), &e) {
+			t.Fatal("the error was not found")
+		}
+
+		if _, ok := e.(*timeout); !ok {
+			t.Errorf("bad error: %T", e)
+		}
+	})
+
+	t.Run("missing type", func(t *testing.T) {
+		var e *errorWithNilCause
+
+		if AsNearest(err, &e) {
+			t.Error("unexpected error:", e)
+		}
+
+		if AsNearest(nil, &e) {
+			t.Error("unexpected error from nil:", e)
+		}
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("searching with a non-pointer target must panic")
+			}
+		}()
+		AsNearest(err, domainError{})
+	})
+}
+
+type depthError struct{ depth int }
+
+func (e *depthError) Error() string { return fmt.Sprintf("depth %d", e.depth) }
+
+func TestValidation(t *testing.T) {
+	err := Validation(
+		FieldError{Field: "email", Message: "missing @"},
+		FieldError{Field: "age", Message: "must be positive"},
+	)
+
+	if !Is("Validation", err) {
+		t.Error("the aggregated error must be of type Validation")
+	}
+
+	if !AllCausesHaveType(err, "Validation") {
+		t.Error("all causes of the aggregated error must be of type Validation")
+	}
+
+	causes := Causes(err)
+
+	if len(causes) != 2 {
+		t.Fatal("bad number of causes:", len(causes))
+	}
+
+	for i, test := range []struct {
+		field   string
+		message string
+	}{
+		{field: "email", message: "missing @"},
+		{field: "age", message: "must be positive"},
+	} {
+		cause := causes[i]
+
+		if !Is("Validation", cause) {
+			t.Errorf("cause %d must be of type Validation", i)
+		}
+
+		if tags := Tags(cause); !reflect.DeepEqual(tags, []Tag{T("field", test.field)}) {
+			t.Errorf("bad tags of cause %d: %v", i, tags)
+		}
+
+		if msg := cause.Error(); msg != test.message {
+			t.Errorf("bad message of cause %d: %q", i, msg)
+		}
+
+		if !HasAnyStack(cause) {
+			t.Errorf("cause %d has no stack trace", i)
+		}
+	}
+
+	if Validation() != nil {
+		t.Error("validation without fields must return nil")
+	}
+}
+
+func TestWrapTyped(t *testing.T) {
+	if err := WrapTyped(nil, "failed to fetch", "Temporary"); err != nil {
+		t.Error("wrapping a nil error must return nil:", err)
+	}
+
+	err := WrapTyped(errors.New("connection refused"), "failed to fetch", "Temporary", "Unavailable")
+
+	if s := err.Error(); s != "failed to fetch: connection refused" {
+		t.Error("bad error message:", s)
+	}
+
+	if types := Types(err); !reflect.DeepEqual(types, []string{"Temporary", "Unavailable"}) {
+		t.Error("bad error types:", types)
+	}
+
+	_, _, _, stacks, _ := Inspect(err)
+
+	if len(stacks) != 1 {
+		t.Fatal("bad number of stack traces:", len(stacks))
+	}
+
+	if name := fmt.Sprintf("%n", stacks[0][0]); name != "TestWrapTyped" {
+		t.Error("bad function name of the first frame of the stack trace:", name)
+	}
+
+	if s := fmt.Sprintf("%+v", err); !strings.HasPrefix(s, "failed to fetch: connection refused (Temporary Unavailable)\n") {
+		t.Error("bad error format:", s)
+	}
+}
+
+func TestMessageChain(t *testing.T) {
+	tests := []struct {
+		scenario string
+		err      error
+		msgs     []string
+	}{
+		{
+			scenario: "nil error",
+			err:      nil,
+			msgs:     nil,
+		},
+
+		{
+			scenario: "wrapped messages",
+			err:      WithMessage(Wrap(New("inner"), "middle"), "outer"),
+			msgs:     []string{"outer", "middle", "inner"},
+		},
+
+		{
+			scenario: "wrapped foreign error",
+			err:      WithTypes(Wrap(errors.New("inner"), "outer"), "Temporary"),
+			msgs:     []string{"outer", "inner"},
+		},
+
+		{
+			scenario: "empty messages are skipped",
+			err:      Wrap(WithMessage(errors.New(""), ""), "outer"),
+			msgs:     []string{"outer"},
+		},
+
+		{
+			scenario: "the chain stops at errors with multiple causes",
+			err:      Wrap(Join(New("A"), New("B")), "outer"),
+			msgs:     []string{"outer"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			if msgs := MessageChain(test.err); !reflect.DeepEqual(msgs, test.msgs) {
+				t.Error("bad message chain:")
+				t.Logf("expected: %q", test.msgs)
+				t.Logf("found:    %q", msgs)
+			}
+		})
+	}
+}
+
+func TestWithoutStack(t *testing.T) {
+	err := WithTags(Wrap(Join(New("A"), WithTypes(New("B"), "Timeout")), "outer"), T("env", "prod"))
+	masked := WithoutStack(err)
+
+	if s := fmt.Sprintf("%+v", masked); strings.Contains(s, "errors_test.go") {
+		t.Error("the formatted error must not contain stack frames:", s)
+	}
+
+	var checkStacks func(Value)
+	checkStacks = func(v Value) {
+		if len(v.Stack) != 0 {
+			t.Errorf("the value of %q has a stack trace", v.Message)
+		}
+		for _, cause := range v.Causes {
+			checkStacks(cause)
+		}
+	}
+
+	v := ValueOf(masked)
+	checkStacks(v)
+
+	if !v.Equal(ValueOf(err), IgnoreStacks()) {
+		t.Error("messages, types, tags, and causes must be preserved")
+		t.Log("expected:", ValueOf(err))
+		t.Log("found:   ", v)
+	}
+
+	if s1, s2 := masked.Error(), err.Error(); s1 != s2 {
+		t.Errorf("bad error message: %q != %q", s1, s2)
+	}
+
+	if HasAnyStack(masked) {
+		t.Error("HasAnyStack must ignore masked stack traces")
+	}
+
+	if _, _, _, stacks, _ := Inspect(WithStack(masked)); len(stacks) != 1 {
+		t.Error("stack traces captured after masking must be preserved:", len(stacks))
+	}
+
+	if WithoutStack(nil) != nil {
+		t.Error("masking the stack traces of a nil error must return nil")
+	}
+}
@@ -2,6 +2,8 @@ package errorstest
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	errors "github.com/segmentio/errors-go"
@@ -12,6 +14,15 @@ type AdapterTest struct {
 	Message string
 	Types   []string
 	Tags    []errors.Tag
+
+	// When true, the adapted error is expected to expose a non-empty stack
+	// trace.
+	Stack bool
+
+	// List of function names expected to be found in the frames of the stack
+	// traces exposed by the adapted error, matched like AssertStackContains
+	// does. Setting this field implies Stack.
+	StackContains []string
 }
 
 func TestAdapter(t *testing.T, a errors.Adapter, tests ...AdapterTest) {
@@ -55,6 +66,10 @@ func TestAdapter(t *testing.T, a errors.Adapter, tests ...AdapterTest) {
 			if cause := errors.Cause(err); cause != test.Error {
 				t.Error("invalid cause:", cause)
 			}
+
+			if test.Stack || len(test.StackContains) != 0 {
+				testStack(t, err, test.StackContains)
+			}
 		})
 	}
 
@@ -70,6 +85,79 @@ func TestAdapter(t *testing.T, a errors.Adapter, tests ...AdapterTest) {
 	}
 }
 
+// AdapterValueRoundTrip adapts err with a, then verifies that the types, tags,
+// and messages of the adapted error are preserved when converting it to a
+// errors.Value and back to an error.
+func AdapterValueRoundTrip(t *testing.T, a errors.Adapter, err error) {
+	t.Run(fmt.Sprintf("%T(%v)", err, err), func(t *testing.T) {
+		adapted, ok := a.Adapt(err)
+
+		if !ok {
+			t.Error("the error was not recognized")
+			return
+		}
+
+		value := errors.ValueOf(adapted)
+		rebuilt := value.Err()
+
+		if rebuilt == nil {
+			t.Error("the error rebuilt from its value is nil")
+			return
+		}
+
+		if t1, t2 := errors.Types(adapted), errors.Types(rebuilt); !typesEqual(t1, t2) {
+			t.Error("types mismatch on the error rebuilt from its value")
+			t.Log("expected:", t1)
+			t.Log("found:   ", t2)
+		}
+
+		if t1, t2 := tagsMap(errors.Tags(adapted)), tagsMap(errors.Tags(rebuilt)); !reflect.DeepEqual(t1, t2) {
+			t.Error("tags mismatch on the error rebuilt from its value")
+			t.Log("expected:", t1)
+			t.Log("found:   ", t2)
+		}
+
+		if m1, m2 := messages(adapted), messages(rebuilt); m1 != m2 {
+			t.Error("messages mismatch on the error rebuilt from its value")
+			t.Log("expected:", m1)
+			t.Log("found:   ", m2)
+		}
+	})
+}
+
+func testStack(t *testing.T, err error, funcNames []string) {
+	_, _, _, stacks, _ := errors.Inspect(err)
+
+	if len(stacks) == 0 {
+		t.Errorf("%#v has no stack trace", err)
+		return
+	}
+
+	for _, funcName := range funcNames {
+		if !stacksContainFunc(stacks, funcName) {
+			t.Errorf("no frames of the stack traces are in function %q", funcName)
+			for _, stack := range stacks {
+				t.Logf("%#v", stack)
+			}
+		}
+	}
+}
+
+func messages(err error) string {
+	msgs, _, _, _, _ := errors.Inspect(err)
+	return strings.Join(msgs, ": ")
+}
+
+func tagsMap(tags []errors.Tag) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, t := range tags {
+		if _, exists := m[t.Name]; !exists {
+			m[t.Name] = t.Value
+		}
+	}
+	return m
+}
+
 func message(err error) string {
 	if e, ok := err.(interface {
 		Message() string
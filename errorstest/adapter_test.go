@@ -0,0 +1,26 @@
+package errorstest_test
+
+import (
+	"net"
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+	"github.com/segmentio/errors-go/errorstest"
+	"github.com/segmentio/errors-go/neterrors"
+	"github.com/segmentio/errors-go/twirperrors"
+	"github.com/twitchtv/twirp"
+)
+
+func TestAdapterValueRoundTrip(t *testing.T) {
+	t.Run("neterrors", func(t *testing.T) {
+		a := errors.AdapterFunc(neterrors.Adapt)
+		errorstest.AdapterValueRoundTrip(t, a, &net.DNSError{Err: "no such host", Name: "localhost", IsTimeout: true})
+		errorstest.AdapterValueRoundTrip(t, a, &net.AddrError{Err: "invalid MAC address"})
+	})
+
+	t.Run("twirperrors", func(t *testing.T) {
+		a := errors.AdapterFunc(twirperrors.Adapt)
+		errorstest.AdapterValueRoundTrip(t, a, twirp.NewError(twirp.NotFound, "no such key"))
+		errorstest.AdapterValueRoundTrip(t, a, twirp.NewError(twirp.Unavailable, "").WithMeta("region", "us-west-2"))
+	})
+}
@@ -0,0 +1,76 @@
+package errorstest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+)
+
+// ConstructorTest describes a test case for TestConstructor.
+type ConstructorTest struct {
+	// Types and tags set on the error passed to the constructor.
+	Types []string
+	Tags  []errors.Tag
+
+	// Code expected to be produced by the constructor.
+	Code string
+
+	// Metadata expected to be produced by the constructor, nil means that the
+	// metadata is not verified.
+	Meta map[string]string
+}
+
+// TestConstructor tests a function converting errors of the errors-go package
+// into errors of another package, like twirperrors.New. Because the output of
+// those functions differ, construct is expected to call the constructor and
+// return the code and metadata of the produced error:
+//
+//	errorstest.TestConstructor(t, func(err error) (string, map[string]string) {
+//		twerr := twirperrors.New(err)
+//		return string(twerr.Code()), twerr.MetaMap()
+//	},
+//		errorstest.ConstructorTest{
+//			Types: []string{"NotFound"},
+//			Code:  "not_found",
+//		},
+//	)
+//
+// For each test case, the error passed to construct has the test types and tags.
+func TestConstructor(t *testing.T, construct func(error) (code string, meta map[string]string), tests ...ConstructorTest) {
+	for _, test := range tests {
+		t.Run(constructorTestName(test), func(t *testing.T) {
+			err := errors.WithTags(errors.WithTypes(errors.New("test error"), test.Types...), test.Tags...)
+			code, meta := construct(err)
+
+			if code != test.Code {
+				t.Error("code mismatch")
+				t.Log("expected:", test.Code)
+				t.Log("found:   ", code)
+			}
+
+			if test.Meta != nil && !metaEqual(meta, test.Meta) {
+				t.Error("metadata mismatch")
+				t.Log("expected:", test.Meta)
+				t.Log("found:   ", meta)
+			}
+		})
+	}
+}
+
+func constructorTestName(test ConstructorTest) string {
+	name := strings.Join(test.Types, ",")
+	if name == "" {
+		name = "(no types)"
+	}
+	if len(test.Tags) != 0 {
+		name += fmt.Sprint(test.Tags)
+	}
+	return name
+}
+
+func metaEqual(m1, m2 map[string]string) bool {
+	return (len(m1) == 0 && len(m2) == 0) || reflect.DeepEqual(m1, m2)
+}
@@ -0,0 +1,42 @@
+package errorstest_test
+
+import (
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+	"github.com/segmentio/errors-go/errorstest"
+	"github.com/segmentio/errors-go/twirperrors"
+)
+
+func TestConstructorTwirp(t *testing.T) {
+	errorstest.TestConstructor(t, func(err error) (string, map[string]string) {
+		twerr := twirperrors.New(err)
+		return string(twerr.Code()), twerr.MetaMap()
+	},
+		errorstest.ConstructorTest{
+			Code: "unknown",
+			Meta: map[string]string{},
+		},
+
+		errorstest.ConstructorTest{
+			Types: []string{"NotFound"},
+			Code:  "not_found",
+			Meta:  map[string]string{twirperrors.TypesMetaKey: `["NotFound"]`},
+		},
+
+		errorstest.ConstructorTest{
+			Types: []string{"Throttled", "Temporary"},
+			Tags:  []errors.Tag{{Name: "region", Value: "us-west-2"}},
+			Code:  "resource_exhausted",
+			Meta: map[string]string{
+				"region":                 "us-west-2",
+				twirperrors.TypesMetaKey: `["Temporary","Throttled"]`,
+			},
+		},
+
+		errorstest.ConstructorTest{
+			Types: []string{"Validation"},
+			Code:  "invalid_argument",
+		},
+	)
+}
@@ -0,0 +1,60 @@
+package errorstest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+)
+
+// AssertStackContains fails the test if none of the frames of the stack traces
+// carried by err are in a function named funcName. The stack traces are those
+// returned by errors.Inspect, which stops at errors that have multiple causes.
+//
+// The function name may be fully qualified (e.g.
+// "github.com/segmentio/errors-go.Wrap") or only contain its last components
+// (e.g. "errors-go.Wrap" or "Wrap"). Methods and closures are named the way the
+// runtime reports them, for example "(*T).Method" or "F.func1".
+func AssertStackContains(t *testing.T, err error, funcName string) {
+	_, _, _, stacks, _ := errors.Inspect(err)
+
+	if len(stacks) == 0 {
+		t.Errorf("%#v has no stack trace", err)
+		return
+	}
+
+	if !stacksContainFunc(stacks, funcName) {
+		t.Errorf("no frames of the stack traces are in function %q", funcName)
+		for _, stack := range stacks {
+			t.Logf("%#v", stack)
+		}
+	}
+}
+
+func stacksContainFunc(stacks []errors.StackTrace, funcName string) bool {
+	for _, stack := range stacks {
+		for _, frame := range stack {
+			if funcNameMatch(fmt.Sprintf("%#n", frame), funcName) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func funcNameMatch(name, funcName string) bool {
+	if !strings.HasSuffix(name, funcName) {
+		return false
+	}
+	if n := len(name) - len(funcName); n != 0 {
+		// The match must start at the beginning of a path segment or of an
+		// identifier, so "Wrap" does not match "errors.MustWrap".
+		switch name[n-1] {
+		case '/', '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,29 @@
+package errorstest
+
+import "testing"
+
+func TestFuncNameMatch(t *testing.T) {
+	const name = "github.com/segmentio/errors-go.(*T).Method"
+
+	tests := []struct {
+		funcName string
+		match    bool
+	}{
+		{funcName: name, match: true},
+		{funcName: "errors-go.(*T).Method", match: true},
+		{funcName: "(*T).Method", match: true},
+		{funcName: "Method", match: true},
+		{funcName: "ethod", match: false},
+		{funcName: "rors-go.(*T).Method", match: false},
+		{funcName: "Method2", match: false},
+		{funcName: "(*T).Other", match: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.funcName, func(t *testing.T) {
+			if match := funcNameMatch(name, test.funcName); match != test.match {
+				t.Errorf("funcNameMatch(%q, %q) = %t", name, test.funcName, match)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package errorstest_test
+
+import (
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+	"github.com/segmentio/errors-go/errorstest"
+)
+
+func TestAdapterStack(t *testing.T) {
+	errorstest.TestAdapter(t, errors.AdapterFunc(adaptWithStack),
+		errorstest.AdapterTest{
+			Error: &stackless{},
+			Types: []string{},
+			Stack: true,
+		},
+
+		errorstest.AdapterTest{
+			Error:         &stackless{},
+			Types:         []string{},
+			StackContains: []string{"errorstest_test.adaptWithStack", "errorstest.TestAdapter.func1"},
+		},
+	)
+}
+
+type stackless struct{}
+
+func (*stackless) Error() string { return "stackless" }
+
+type withStack struct {
+	cause error
+	stack errors.StackTrace
+}
+
+func (e *withStack) Error() string                 { return e.cause.Error() }
+func (e *withStack) Cause() error                  { return e.cause }
+func (e *withStack) StackTrace() errors.StackTrace { return e.stack }
+
+// adaptWithStack is an adapter which captures the stack trace where errors are
+// adapted.
+func adaptWithStack(err error) (error, bool) {
+	if _, ok := err.(*stackless); ok {
+		return &withStack{cause: err, stack: errors.CaptureStackTrace(0)}, true
+	}
+	return err, false
+}
+
+func TestAssertStackContains(t *testing.T) {
+	err := errors.New("oops")
+
+	errorstest.AssertStackContains(t, err, "TestAssertStackContains")
+	errorstest.AssertStackContains(t, err, "errorstest_test.TestAssertStackContains")
+	errorstest.AssertStackContains(t, err, "github.com/segmentio/errors-go/errorstest_test.TestAssertStackContains")
+
+	errorstest.AssertStackContains(t, errors.Wrap(wrapStackless(), "wrapped"), "errorstest_test.wrapStackless")
+	errorstest.AssertStackContains(t, errors.Wrap(wrapStackless(), "wrapped"), "TestAssertStackContains")
+}
+
+func wrapStackless() error {
+	return errors.Wrap(&stackless{}, "stackless")
+}
@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"reflect"
+	"strings"
+)
+
+// Fingerprint returns a hash of the structure of err, which programs can use to
+// group errors that are considered the same, for example to deduplicate alerts.
+//
+// The fingerprint is computed from the messages and types of err and of all its
+// causes, as well as the shape of the graph of causes. Tags and stack traces are
+// not included since they usually differ between occurrences of the same error.
+// Masks set by WithoutTypes are honored, so masked types do not change the
+// fingerprint of an error.
+//
+// The returned value is a string of 16 hexadecimal characters, or an empty
+// string if err is nil. Fingerprints are stable across runs of a program, but
+// may change between versions of the package.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	f := fingerprinter{hash: fnv.New64a()}
+	f.writeError(err, nil)
+	return f.String()
+}
+
+// FingerprintWithOrigin is like Fingerprint but the function, file, and line of
+// the frame where err originates are also part of the hash, so errors with the
+// same structure created by different code paths have different fingerprints.
+//
+// The origin is the first frame of the outermost stack trace carried by err or
+// its causes, skipping frames of the errors-go packages and of the packages
+// registered with RegisterInternalPackage. Errors without such frame all share
+// the same empty origin.
+func FingerprintWithOrigin(err error) string {
+	if err == nil {
+		return ""
+	}
+	f := fingerprinter{hash: fnv.New64a()}
+	f.writeError(err, nil)
+
+	if frame, ok := originFrame(err); ok {
+		file, line, name := frame.source()
+		f.writeUint(1)
+		f.writeString(name)
+		f.writeString(file)
+		f.writeUint(uint64(line))
+	} else {
+		f.writeUint(0)
+	}
+
+	return f.String()
+}
+
+// originFrame returns the first frame of the outermost stack trace of err which
+// is not in the errors-go packages or in an internal package.
+func originFrame(err error) (origin Frame, found bool) {
+	walkPath(err, nil, func(e error) bool {
+		if _, masked := e.(*errorWithoutStack); masked {
+			return false
+		}
+		for _, frame := range stackTrace(e) {
+			if !isInternalFrame(frame) {
+				origin, found = frame, true
+				break
+			}
+		}
+		return !found
+	})
+	return
+}
+
+// packagePath is the import path of the errors-go package, used to recognize
+// frames of the package and its sub-packages.
+var packagePath = reflect.TypeOf(Frame(0)).PkgPath()
+
+func isInternalFrame(frame Frame) bool {
+	name := frame.name()
+	if internalPackages.match(name) {
+		return true
+	}
+	return strings.HasPrefix(name, packagePath+".") || strings.HasPrefix(name, packagePath+"/")
+}
+
+type fingerprinter struct {
+	hash hash.Hash64
+	buf  [binary.MaxVarintLen64]byte
+}
+
+func (f *fingerprinter) String() string {
+	return fmt.Sprintf("%016x", f.hash.Sum64())
+}
+
+func (f *fingerprinter) writeError(err error, path errorPath) {
+	msgs, types, _, _, causes := Inspect(err)
+	f.writeStrings(msgs)
+	f.writeStrings(types)
+
+	path = append(path, err)
+	f.writeUint(uint64(len(causes)))
+
+	for _, cause := range causes {
+		if path.contains(cause) {
+			f.writeUint(0)
+		} else {
+			f.writeError(cause, path)
+		}
+	}
+}
+
+func (f *fingerprinter) writeStrings(list []string) {
+	f.writeUint(uint64(len(list)))
+	for _, s := range list {
+		f.writeString(s)
+	}
+}
+
+func (f *fingerprinter) writeString(s string) {
+	f.writeUint(uint64(len(s)))
+	f.hash.Write([]byte(s))
+}
+
+func (f *fingerprinter) writeUint(u uint64) {
+	n := binary.PutUvarint(f.buf[:], u)
+	f.hash.Write(f.buf[:n])
+}
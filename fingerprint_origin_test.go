@@ -0,0 +1,42 @@
+package errors_test
+
+import (
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+)
+
+// The tests of FingerprintWithOrigin are in an external test package because
+// frames of the errors-go packages, including the ones of their internal tests,
+// are skipped when looking for the origin of errors.
+
+func TestFingerprintWithOrigin(t *testing.T) {
+	err1 := connectionRefusedA()
+	err2 := connectionRefusedB()
+
+	if errors.Fingerprint(err1) != errors.Fingerprint(err2) {
+		t.Error("errors with the same structure must have the same fingerprint")
+	}
+
+	if errors.FingerprintWithOrigin(err1) == errors.FingerprintWithOrigin(err2) {
+		t.Error("errors created by different functions must have different fingerprints when the origin is included")
+	}
+
+	if errors.FingerprintWithOrigin(err1) != errors.FingerprintWithOrigin(connectionRefusedA()) {
+		t.Error("errors created at the same place must have the same fingerprint when the origin is included")
+	}
+
+	if errors.FingerprintWithOrigin(err1) == errors.Fingerprint(err1) {
+		t.Error("the origin must be part of the fingerprint")
+	}
+
+	if errors.FingerprintWithOrigin(nil) != "" {
+		t.Error("the fingerprint of a nil error must be empty")
+	}
+}
+
+func connectionRefusedA() error {
+	return errors.WithTags(errors.New("connection refused"), errors.T("attempt", "1"))
+}
+
+func connectionRefusedB() error { return errors.New("connection refused") }
@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	makeError := func(id string) error {
+		return WithTags(
+			Wrap(Join(
+				WithTypes(New("connection refused"), "Temporary"),
+				errors.New("timeout"),
+			), "fetching user"),
+			T("user-id", id),
+		)
+	}
+
+	err1 := makeError("1")
+	err2 := makeError("2")
+
+	if Fingerprint(err1) != Fingerprint(err2) {
+		t.Error("structurally-equivalent errors must have the same fingerprint")
+	}
+
+	if f := Fingerprint(err1); len(f) != 16 {
+		t.Errorf("bad fingerprint length: %q", f)
+	}
+
+	for _, test := range []struct {
+		scenario string
+		err      error
+	}{
+		{
+			scenario: "different message",
+			err:      WithTags(Wrap(Join(WithTypes(New("connection reset"), "Temporary"), errors.New("timeout")), "fetching user"), T("user-id", "1")),
+		},
+
+		{
+			scenario: "different types",
+			err:      WithTags(Wrap(Join(WithTypes(New("connection refused"), "Timeout"), errors.New("timeout")), "fetching user"), T("user-id", "1")),
+		},
+
+		{
+			scenario: "different order of causes",
+			err:      WithTags(Wrap(Join(errors.New("timeout"), WithTypes(New("connection refused"), "Temporary")), "fetching user"), T("user-id", "1")),
+		},
+
+		{
+			scenario: "different wrapping message",
+			err:      WithTags(Wrap(Join(WithTypes(New("connection refused"), "Temporary"), errors.New("timeout")), "fetching"), T("user-id", "1")),
+		},
+
+		{
+			scenario: "masked types",
+			err:      WithoutTypes(err1, "Temporary"),
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			if Fingerprint(err1) == Fingerprint(test.err) {
+				t.Error("structurally-different errors must have different fingerprints")
+			}
+		})
+	}
+
+	if Fingerprint(WithoutTags(err1, "user-id")) != Fingerprint(err1) {
+		t.Error("tags must not be part of the fingerprint")
+	}
+
+	if Fingerprint(nil) != "" {
+		t.Error("the fingerprint of a nil error must be empty")
+	}
+}
@@ -1,11 +1,88 @@
 package errors
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"os"
 	"strings"
+	"sync/atomic"
 )
 
+// Limits applied by the formatter when printing errors with the "%v" and "%+v"
+// formats, zero means unlimited.
+var (
+	maxFormatDepth  int64
+	maxFormatCauses int64
+)
+
+// ANSI escape sequences used by the formatter when colors are enabled.
+const (
+	colorReset = "\x1b[0m"
+	colorTypes = "\x1b[36m"
+	colorTags  = "\x1b[33m"
+	colorFrame = "\x1b[90m"
+)
+
+// formatColors is set to 1 when the formatter must emit colors.
+var formatColors int32
+
+// SetFormatColors enables or disables the use of ANSI colors around the types,
+// tags, and source locations of stack frames when printing errors with Fprint.
+// Colors are disabled by default.
+//
+// Colors are only emitted when the writer passed to Fprint is a terminal. The
+// "%v" and "%+v" formats never emit colors because the formatter has no access
+// to the writer that the output is sent to.
+func SetFormatColors(enable bool) {
+	var v int32
+	if enable {
+		v = 1
+	}
+	atomic.StoreInt32(&formatColors, v)
+}
+
+// Fprint writes err to w in the format of "%+v", using colors if they were
+// enabled with SetFormatColors and w is a terminal:
+//
+//	errors.SetFormatColors(true)
+//	errors.Fprint(os.Stderr, err)
+//
+// The function returns the number of bytes written and any write error.
+func Fprint(w io.Writer, err error) (int, error) {
+	s := &renderState{plus: true}
+	f := formatter{state: s, colors: atomic.LoadInt32(&formatColors) != 0 && isTerminal(w)}
+	f.format(formatterContext{length: 1}, nil, err)
+	return w.Write(s.Bytes())
+}
+
+// isTerminal reports whether w is a terminal, it is declared as a variable so
+// tests can override it.
+var isTerminal = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && (fi.Mode()&os.ModeCharDevice) != 0
+}
+
+// SetMaxFormatDepth sets the maximum depth of the tree of causes printed when
+// formatting errors with "%v" or "%+v". Causes below this depth are omitted and
+// replaced with a "... and N more" line. A value of zero or less removes the
+// limit, which is the default.
+func SetMaxFormatDepth(n int) {
+	atomic.StoreInt64(&maxFormatDepth, int64(n))
+}
+
+// SetMaxFormatCauses sets the maximum number of causes printed under each node
+// of the tree when formatting errors with "%v" or "%+v". Causes beyond the limit
+// are omitted and replaced with a "... and N more" line. A value of zero or less
+// removes the limit, which is the default.
+func SetMaxFormatCauses(n int) {
+	atomic.StoreInt64(&maxFormatCauses, int64(n))
+}
+
 // format is the implementation of a generic error formatting functions which
 // supports the following verbs:
 //
@@ -17,6 +94,7 @@ import (
 //
 //    %+v   similar to %v but prints the stack traces below the messages
 //    %#v   prints errors with their messages and causes in  Go-like syntax
+//    % v   prints the error messages and causes on a single line
 func format(s fmt.State, v rune, err error) {
 	switch v {
 	case 's':
@@ -26,12 +104,16 @@ func format(s fmt.State, v rune, err error) {
 		fmt.Fprintf(s, "%q", err.Error())
 
 	case 'v':
-		if s.Flag('#') {
+		switch {
+		case s.Flag('#'):
 			f := goformatter{state: s}
 			f.format(err)
-		} else {
+		case s.Flag(' '):
+			f := onelineFormatter{w: s}
+			f.format(nil, err)
+		default:
 			f := formatter{state: s}
-			f.format(formatterContext{length: 1}, err)
+			f.format(formatterContext{length: 1}, nil, err)
 		}
 
 	default:
@@ -42,6 +124,7 @@ func format(s fmt.State, v rune, err error) {
 type formatterContext struct {
 	index       int  // index in the parent list of causes
 	length      int  // length of the parent list of causes
+	depth       int  // depth of the node in the tree of causes
 	needNewLine bool // whether a new line must be printed
 }
 
@@ -57,28 +140,79 @@ func (fctx *formatterContext) last() bool {
 type formatter struct {
 	state  fmt.State
 	indent indent
+	colors bool
 }
 
-func (f *formatter) format(fctx formatterContext, err error) {
+func (f *formatter) format(fctx formatterContext, path errorPath, err error) {
 	msgs, types, tags, stacks, causes := Inspect(err)
+	path = path.appendChain(err)
+	causes = path.exclude(causes)
 
 	if len(msgs) == 0 {
 		msgs = []string{"."}
 	}
 
-	f.writeNode(fctx, msgs, types, tags, stacks)
+	var meta *Metadata
+	if m, ok := inspectMetadata(err); ok {
+		meta = &m
+	}
+
+	f.writeNode(fctx, msgs, types, tags, stacks, meta)
 	f.indent.push(fctx)
 	defer f.indent.pop()
 
-	fctx.length = len(causes)
+	limit := formatLimit(fctx.depth, len(causes))
+
+	fctx.length = limit
+	fctx.depth++
 	fctx.needNewLine = true
 
-	for i, cause := range causes {
+	if limit < len(causes) {
+		fctx.length++
+	}
+
+	for i, cause := range causes[:limit] {
 		fctx.index = i
-		f.format(fctx, cause)
+		f.format(fctx, path, cause)
+	}
+
+	if limit < len(causes) {
+		fctx.index = limit
+		f.writeNode(fctx, []string{fmt.Sprintf("... and %d more", len(causes)-limit)}, nil, nil, nil, nil)
 	}
 }
 
+// Oneline returns a representation of err and the graph of its causes on a
+// single line, which is the same output as formatting err with "% v".
+//
+// Levels of causes are separated by " <- ", and groups of causes are enclosed
+// in braces:
+//
+//	answer 42 <- {A: TODO; B (Timeout); C [env:"production"]}
+//
+// If err is nil, the function returns an empty string.
+func Oneline(err error) string {
+	if err == nil {
+		return ""
+	}
+	b := &bytes.Buffer{}
+	f := onelineFormatter{w: b}
+	f.format(nil, err)
+	return b.String()
+}
+
+// formatLimit returns how many of the n causes of a node at the given depth
+// should be printed by the formatter.
+func formatLimit(depth int, n int) int {
+	if maxDepth := atomic.LoadInt64(&maxFormatDepth); maxDepth > 0 && int64(depth) >= maxDepth {
+		return 0
+	}
+	if maxCauses := atomic.LoadInt64(&maxFormatCauses); maxCauses > 0 && int64(n) > maxCauses {
+		return int(maxCauses)
+	}
+	return n
+}
+
 func (f *formatter) writeNewLine(fctx formatterContext) {
 	f.writeString("\n")
 	f.indent.nextLine(fctx)
@@ -92,7 +226,7 @@ func (f *formatter) writeIndent() {
 	f.indent.writeTo(f.state)
 }
 
-func (f *formatter) writeNode(fctx formatterContext, msgs []string, types []string, tags []Tag, stacks []StackTrace) {
+func (f *formatter) writeNode(fctx formatterContext, msgs []string, types []string, tags []Tag, stacks []StackTrace, meta *Metadata) {
 	if fctx.needNewLine {
 		f.writeNewLine(fctx)
 	}
@@ -112,37 +246,34 @@ func (f *formatter) writeNode(fctx formatterContext, msgs []string, types []stri
 	f.writeTags(tags)
 
 	if f.state.Flag('+') {
+		if meta != nil {
+			f.writeNewLine(fctx)
+			f.writeIndent()
+			f.writeString(meta.String())
+		}
 		f.writeStacks(fctx, stacks)
 	}
 }
 
 func (f *formatter) writeTypes(types []string) {
 	if len(types) != 0 {
-		f.writeString(" (")
-
-		for i, t := range types {
-			if i != 0 {
-				f.writeString(" ")
-			}
-			f.writeString(t)
-		}
-
-		f.writeString(")")
+		f.setColor(colorTypes)
+		writeTypes(f.state, types)
+		f.setColor(colorReset)
 	}
 }
 
 func (f *formatter) writeTags(tags []Tag) {
 	if len(tags) != 0 {
-		f.writeString(" [")
-
-		for i, t := range tags {
-			if i != 0 {
-				f.writeString(" ")
-			}
-			fmt.Fprintf(f.state, "%s:%q", t.Name, t.Value)
-		}
+		f.setColor(colorTags)
+		writeTags(f.state, tags)
+		f.setColor(colorReset)
+	}
+}
 
-		f.writeString("]")
+func (f *formatter) setColor(color string) {
+	if f.colors {
+		f.writeString(color)
 	}
 }
 
@@ -174,7 +305,67 @@ func (f *formatter) writeFrameFunc(fctx formatterContext, frame Frame) {
 func (f *formatter) writeFrameFile(fctx formatterContext, frame Frame) {
 	f.writeNewLine(fctx)
 	f.writeIndent()
-	fmt.Fprintf(f.state, "\t%+s:%d", frame, frame)
+	f.writeString("\t")
+	f.setColor(colorFrame)
+	fmt.Fprintf(f.state, "%+s:%d", frame, frame)
+	f.setColor(colorReset)
+}
+
+// onelineFormatter is an error formatter which prints errors and the graph of
+// their causes on a single line. It is used when writing errors with the "% v"
+// format.
+type onelineFormatter struct {
+	w io.Writer
+}
+
+func (f *onelineFormatter) format(path errorPath, err error) {
+	msgs, types, tags, _, causes := Inspect(err)
+	depth := len(path)
+	path = path.appendChain(err)
+	causes = path.exclude(causes)
+
+	if len(msgs) == 0 {
+		msgs = []string{"."}
+	}
+
+	f.writeString(strings.Replace(strings.Join(msgs, ": "), "\n", `\n`, -1))
+	writeTypes(f.w, types)
+	writeTags(f.w, tags)
+
+	if len(causes) == 0 {
+		return
+	}
+
+	limit := formatLimit(depth, len(causes))
+	group := len(causes) > 1
+
+	f.writeString(" <- ")
+
+	if group {
+		f.writeString("{")
+	}
+
+	for i, cause := range causes[:limit] {
+		if i != 0 {
+			f.writeString("; ")
+		}
+		f.format(path, cause)
+	}
+
+	if limit < len(causes) {
+		if limit != 0 {
+			f.writeString("; ")
+		}
+		fmt.Fprintf(f.w, "... and %d more", len(causes)-limit)
+	}
+
+	if group {
+		f.writeString("}")
+	}
+}
+
+func (f *onelineFormatter) writeString(s string) {
+	io.WriteString(f.w, s)
 }
 
 // goformatter is an error formatter which prints errors with their message and
@@ -217,6 +408,37 @@ func (f *goformatter) print(s string, a ...interface{}) {
 	fmt.Fprintf(f.state, s, a...)
 }
 
+func writeTypes(w io.Writer, types []string) {
+	if len(types) != 0 {
+		io.WriteString(w, " (")
+
+		for i, t := range types {
+			if i != 0 {
+				io.WriteString(w, " ")
+			}
+			io.WriteString(w, t)
+		}
+
+		io.WriteString(w, ")")
+	}
+}
+
+func writeTags(w io.Writer, tags []Tag) {
+	if len(tags) != 0 {
+		tags = redactTags(tags)
+		io.WriteString(w, " [")
+
+		for i, t := range tags {
+			if i != 0 {
+				io.WriteString(w, " ")
+			}
+			fmt.Fprintf(w, "%s:%q", t.Name, t.Value)
+		}
+
+		io.WriteString(w, "]")
+	}
+}
+
 // indent is a helper type used to format a tree-like representation that
 // supports multi-line nodes.
 type indent struct {
@@ -2,7 +2,10 @@ package errors
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
 )
 
@@ -247,3 +250,226 @@ func TestFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatLimits(t *testing.T) {
+	defer SetMaxFormatDepth(0)
+	defer SetMaxFormatCauses(0)
+
+	errs := make([]error, 100)
+	for i := range errs {
+		errs[i] = Errorf("error %d", i)
+	}
+
+	err := WithMessage(
+		Join(
+			Join(errs...),
+			New("B"),
+			New("C"),
+		),
+		"answer 42",
+	)
+
+	tests := []struct {
+		depth  int
+		causes int
+		string string
+	}{
+		{
+			depth: 1,
+			string: `answer 42
+├── .
+|   └── ... and 100 more
+├── B
+└── C`,
+		},
+
+		{
+			causes: 2,
+			string: `answer 42
+├── .
+|   ├── error 0
+|   ├── error 1
+|   └── ... and 98 more
+├── B
+└── ... and 1 more`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("depth=%d,causes=%d", test.depth, test.causes), func(t *testing.T) {
+			SetMaxFormatDepth(test.depth)
+			SetMaxFormatCauses(test.causes)
+
+			if s := fmt.Sprintf("%v", err); s != test.string {
+				t.Error("bad string:")
+				t.Logf("expected: %s", test.string)
+				t.Logf("found:    %s", s)
+			}
+		})
+	}
+
+	t.Run("unlimited", func(t *testing.T) {
+		SetMaxFormatDepth(0)
+		SetMaxFormatCauses(0)
+
+		s := fmt.Sprintf("%v", err)
+
+		if n := strings.Count(s, "\n") + 1; n != 104 {
+			t.Error("bad number of lines:", n)
+		}
+		if strings.Contains(s, "more") {
+			t.Error("unexpected truncation:", s)
+		}
+	})
+}
+
+func TestFormatOneline(t *testing.T) {
+	err := WithMessage(
+		Join(
+			Join(
+				Wrap(TODO, "A.1"),
+				New("A.2"),
+			),
+			WithTypes(New("B\nmulti-line"), "Timeout", "Temporary"),
+			WithTags(New("C"), T("env", "production")),
+		),
+		"answer 42",
+	)
+
+	multiline := `answer 42
+├── .
+|   ├── A.1: TODO
+|   └── A.2
+├── B
+|   multi-line (Temporary Timeout)
+└── C [env:"production"]`
+
+	oneline := `answer 42 <- {. <- {A.1: TODO; A.2}; B\nmulti-line (Temporary Timeout); C [env:"production"]}`
+
+	if s := fmt.Sprintf("%v", err); s != multiline {
+		t.Error("bad multi-line string:")
+		t.Logf("expected: %s", multiline)
+		t.Logf("found:    %s", s)
+	}
+
+	if s := fmt.Sprintf("% v", err); s != oneline {
+		t.Error("bad single-line string:")
+		t.Logf("expected: %s", oneline)
+		t.Logf("found:    %s", s)
+	}
+
+	if s := Oneline(err); s != oneline {
+		t.Error("bad single-line string:")
+		t.Logf("expected: %s", oneline)
+		t.Logf("found:    %s", s)
+	}
+
+	if s := Oneline(WithMessage(errors.New("hello world"), "answer 42")); s != "answer 42: hello world" {
+		t.Error("bad single-line string:", s)
+	}
+
+	if s := Oneline(nil); s != "" {
+		t.Error("bad single-line string for nil error:", s)
+	}
+}
+
+func TestFormatColors(t *testing.T) {
+	if isTerminal(&bytes.Buffer{}) {
+		t.Error("a buffer must not be considered a terminal")
+	}
+
+	defer func(f func(io.Writer) bool) { isTerminal = f }(isTerminal)
+	defer SetFormatColors(false)
+
+	err := WithTags(
+		WithTypes(New("hello world"), "Timeout"),
+		T("env", "production"),
+	)
+
+	tests := []struct {
+		scenario string
+		terminal bool
+		enable   bool
+		colors   bool
+	}{
+		{
+			scenario: "colors are disabled by default",
+			terminal: true,
+		},
+
+		{
+			scenario: "colors are emitted when enabled on a terminal",
+			terminal: true,
+			enable:   true,
+			colors:   true,
+		},
+
+		{
+			scenario: "colors are never emitted when not writing to a terminal",
+			enable:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			isTerminal = func(io.Writer) bool { return test.terminal }
+			SetFormatColors(test.enable)
+
+			b := &bytes.Buffer{}
+			Fprint(b, err)
+			s := b.String()
+
+			for _, color := range []string{colorTypes, colorTags, colorFrame} {
+				if strings.Contains(s, color) != test.colors {
+					t.Errorf("bad colors in the output: %q", s)
+				}
+			}
+
+			if s != fmt.Sprintf("%+v", err) && !test.colors {
+				t.Errorf("the output differs from %%+v: %q", s)
+			}
+
+			for _, format := range []string{"%s", "%v", "%+v"} {
+				if s := fmt.Sprintf(format, err); strings.Contains(s, "\x1b") {
+					t.Errorf("unexpected colors in %s output: %q", format, s)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatTagRedactor(t *testing.T) {
+	SetTagRedactor(func(tag Tag) Tag {
+		if tag.Name == "authorization" {
+			tag.Value = "***"
+		}
+		return tag
+	})
+	defer SetTagRedactor(nil)
+
+	err := WithTags(New("oops"), T("authorization", "secret"), T("env", "prod"))
+
+	for _, format := range []string{"%v", "%+v"} {
+		s := fmt.Sprintf(format, err)
+
+		if strings.Contains(s, "secret") {
+			t.Errorf("tag value was not redacted in %s output: %q", format, s)
+		}
+		if !strings.Contains(s, `authorization:"***"`) || !strings.Contains(s, `env:"prod"`) {
+			t.Errorf("bad tags in %s output: %q", format, s)
+		}
+	}
+
+	v := ValueOf(err)
+
+	if tag := v.Tags["authorization"]; tag != "***" {
+		t.Error("tag value was not redacted in ValueOf:", tag)
+	}
+	if tag := v.Tags["env"]; tag != "prod" {
+		t.Error("bad tag value in ValueOf:", tag)
+	}
+
+	if tags := Tags(err); tags[0] != T("authorization", "secret") {
+		t.Error("tags of the error must not be redacted:", tags)
+	}
+}
@@ -0,0 +1,100 @@
+package grpcerrors
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Adapt checks whether err carries a gRPC status, and adapts it to make error
+// types discoverable using the errors.Is function. The type of the error is
+// set to the name of the status code, for example an error with the code
+// codes.NotFound will be of type "NotFound".
+//
+// The adapted error still exposes the gRPC status of err through a
+// GRPCStatus() *status.Status method.
+//
+// This function is automatically installed as a global adapter when importing
+// the grpcerrors package, a program likely should use errors.Adapt instead of
+// calling this adapter directly.
+func Adapt(err error) (error, bool) {
+	if _, ok := err.(*grpcError); ok {
+		return err, false
+	}
+	if e, ok := err.(grpcStatus); ok {
+		if s := e.GRPCStatus(); s != nil && s.Code() != codes.OK {
+			return &grpcError{cause: err, status: s}, true
+		}
+	}
+	return err, false
+}
+
+type grpcStatus interface {
+	GRPCStatus() *status.Status
+}
+
+type grpcError struct {
+	cause  error
+	status *status.Status
+}
+
+func (e *grpcError) Cause() error { return e.cause }
+
+func (e *grpcError) Error() string { return e.cause.Error() }
+
+func (e *grpcError) Message() string { return e.status.Message() }
+
+func (e *grpcError) GRPCStatus() *status.Status { return e.status }
+
+// gRPC-specific error types
+
+func (e *grpcError) Canceled() bool { return e.is(codes.Canceled) }
+
+func (e *grpcError) Unknown() bool { return e.is(codes.Unknown) }
+
+func (e *grpcError) InvalidArgument() bool { return e.is(codes.InvalidArgument) }
+
+func (e *grpcError) DeadlineExceeded() bool { return e.is(codes.DeadlineExceeded) }
+
+func (e *grpcError) NotFound() bool { return e.is(codes.NotFound) }
+
+func (e *grpcError) AlreadyExists() bool { return e.is(codes.AlreadyExists) }
+
+func (e *grpcError) PermissionDenied() bool { return e.is(codes.PermissionDenied) }
+
+func (e *grpcError) ResourceExhausted() bool { return e.is(codes.ResourceExhausted) }
+
+func (e *grpcError) FailedPrecondition() bool { return e.is(codes.FailedPrecondition) }
+
+func (e *grpcError) Aborted() bool { return e.is(codes.Aborted) }
+
+func (e *grpcError) OutOfRange() bool { return e.is(codes.OutOfRange) }
+
+func (e *grpcError) Unimplemented() bool { return e.is(codes.Unimplemented) }
+
+func (e *grpcError) Internal() bool { return e.is(codes.Internal) }
+
+func (e *grpcError) Unavailable() bool { return e.is(codes.Unavailable) }
+
+func (e *grpcError) DataLoss() bool { return e.is(codes.DataLoss) }
+
+func (e *grpcError) Unauthenticated() bool { return e.is(codes.Unauthenticated) }
+
+func (e *grpcError) is(code codes.Code) bool { return e.status.Code() == code }
+
+// Common error types
+
+func (e *grpcError) Conflict() bool { return e.AlreadyExists() }
+
+func (e *grpcError) Throttled() bool { return e.ResourceExhausted() }
+
+func (e *grpcError) Timeout() bool { return e.Canceled() || e.DeadlineExceeded() }
+
+func (e *grpcError) Validation() bool { return e.InvalidArgument() || e.OutOfRange() }
+
+func (e *grpcError) Temporary() bool {
+	return e.Timeout() ||
+		e.Throttled() ||
+		e.Aborted() ||
+		e.Internal() ||
+		e.Unavailable()
+}
@@ -0,0 +1,118 @@
+package grpcerrors
+
+import (
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+	"github.com/segmentio/errors-go/errorstest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdapt(t *testing.T) {
+	errorstest.TestAdapter(t, errors.AdapterFunc(Adapt),
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.Canceled, "oops"),
+			Message: "oops",
+			Types:   []string{"Canceled", "Temporary", "Timeout"},
+		},
+
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.Unknown, "oops"),
+			Message: "oops",
+			Types:   []string{"Unknown"},
+		},
+
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.InvalidArgument, "oops"),
+			Message: "oops",
+			Types:   []string{"InvalidArgument", "Validation"},
+		},
+
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.DeadlineExceeded, "oops"),
+			Message: "oops",
+			Types:   []string{"DeadlineExceeded", "Temporary", "Timeout"},
+		},
+
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.NotFound, "oops"),
+			Message: "oops",
+			Types:   []string{"NotFound"},
+		},
+
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.AlreadyExists, "oops"),
+			Message: "oops",
+			Types:   []string{"AlreadyExists", "Conflict"},
+		},
+
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.PermissionDenied, "oops"),
+			Message: "oops",
+			Types:   []string{"PermissionDenied"},
+		},
+
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.ResourceExhausted, "oops"),
+			Message: "oops",
+			Types:   []string{"ResourceExhausted", "Temporary", "Throttled"},
+		},
+
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.FailedPrecondition, "oops"),
+			Message: "oops",
+			Types:   []string{"FailedPrecondition"},
+		},
+
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.Aborted, "oops"),
+			Message: "oops",
+			Types:   []string{"Aborted", "Temporary"},
+		},
+
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.OutOfRange, "oops"),
+			Message: "oops",
+			Types:   []string{"OutOfRange", "Validation"},
+		},
+
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.Unimplemented, "oops"),
+			Message: "oops",
+			Types:   []string{"Unimplemented"},
+		},
+
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.Internal, "oops"),
+			Message: "oops",
+			Types:   []string{"Internal", "Temporary"},
+		},
+
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.Unavailable, "oops"),
+			Message: "oops",
+			Types:   []string{"Temporary", "Unavailable"},
+		},
+
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.DataLoss, "oops"),
+			Message: "oops",
+			Types:   []string{"DataLoss"},
+		},
+
+		errorstest.AdapterTest{
+			Error:   status.Error(codes.Unauthenticated, "oops"),
+			Message: "oops",
+			Types:   []string{"Unauthenticated"},
+		},
+	)
+}
+
+func TestAdaptAdapted(t *testing.T) {
+	err := errors.Adapt(status.Error(codes.NotFound, "oops"))
+
+	if e := errors.Adapt(err); e != err {
+		t.Errorf("%T was adapted again to %T", err, e)
+	}
+}
@@ -0,0 +1,7 @@
+// Package grpcerrors provides functions to adapt errors of the
+// google.golang.org/grpc package into errors compatible with the errors-go
+// package, and to convert errors-go errors into gRPC status errors.
+//
+// Importing this package installs the gRPC errors adapter on the global set of
+// adapters of the parent errors-go package.
+package grpcerrors
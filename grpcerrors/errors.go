@@ -0,0 +1,83 @@
+package grpcerrors
+
+import (
+	"strings"
+
+	errors "github.com/segmentio/errors-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// New constructs a gRPC status error from another error. The status code is
+// guessed by inspecting the types of err, and defaults to codes.Unknown if the
+// error had no types. The message of the status is the message of err.
+//
+// If err already carries a gRPC status it is returned unchanged.
+//
+// If err is nil the function returns nil.
+func New(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := err.(grpcStatus); ok {
+		return err
+	}
+
+	msgs, types, _, _, _ := errors.Inspect(err)
+	return status.Error(code(types), strings.Join(msgs, ": "))
+}
+
+func code(types []string) codes.Code {
+	for _, typ := range types {
+		switch typ {
+		case "Canceled":
+			return codes.Canceled
+		case "Unknown":
+			return codes.Unknown
+		case "InvalidArgument":
+			return codes.InvalidArgument
+		case "DeadlineExceeded":
+			return codes.DeadlineExceeded
+		case "NotFound":
+			return codes.NotFound
+		case "AlreadyExists":
+			return codes.AlreadyExists
+		case "PermissionDenied":
+			return codes.PermissionDenied
+		case "ResourceExhausted":
+			return codes.ResourceExhausted
+		case "FailedPrecondition":
+			return codes.FailedPrecondition
+		case "Aborted":
+			return codes.Aborted
+		case "OutOfRange":
+			return codes.OutOfRange
+		case "Unimplemented":
+			return codes.Unimplemented
+		case "Internal":
+			return codes.Internal
+		case "Unavailable":
+			return codes.Unavailable
+		case "DataLoss":
+			return codes.DataLoss
+		case "Unauthenticated":
+			return codes.Unauthenticated
+		}
+	}
+
+	for _, typ := range types {
+		switch typ {
+		case "Validation":
+			return codes.InvalidArgument
+		case "Timeout":
+			return codes.DeadlineExceeded
+		case "Throttled":
+			return codes.ResourceExhausted
+		case "Conflict":
+			return codes.AlreadyExists
+		}
+	}
+
+	return codes.Unknown
+}
@@ -0,0 +1,159 @@
+package grpcerrors
+
+import (
+	"fmt"
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		types []string
+		code  codes.Code
+	}{
+		{
+			types: []string{"Canceled"},
+			code:  codes.Canceled,
+		},
+
+		{
+			types: []string{"Unknown"},
+			code:  codes.Unknown,
+		},
+
+		{
+			types: []string{"InvalidArgument"},
+			code:  codes.InvalidArgument,
+		},
+
+		{
+			types: []string{"DeadlineExceeded"},
+			code:  codes.DeadlineExceeded,
+		},
+
+		{
+			types: []string{"NotFound"},
+			code:  codes.NotFound,
+		},
+
+		{
+			types: []string{"AlreadyExists"},
+			code:  codes.AlreadyExists,
+		},
+
+		{
+			types: []string{"PermissionDenied"},
+			code:  codes.PermissionDenied,
+		},
+
+		{
+			types: []string{"ResourceExhausted"},
+			code:  codes.ResourceExhausted,
+		},
+
+		{
+			types: []string{"FailedPrecondition"},
+			code:  codes.FailedPrecondition,
+		},
+
+		{
+			types: []string{"Aborted"},
+			code:  codes.Aborted,
+		},
+
+		{
+			types: []string{"OutOfRange"},
+			code:  codes.OutOfRange,
+		},
+
+		{
+			types: []string{"Unimplemented"},
+			code:  codes.Unimplemented,
+		},
+
+		{
+			types: []string{"Internal"},
+			code:  codes.Internal,
+		},
+
+		{
+			types: []string{"Unavailable"},
+			code:  codes.Unavailable,
+		},
+
+		{
+			types: []string{"DataLoss"},
+			code:  codes.DataLoss,
+		},
+
+		{
+			types: []string{"Unauthenticated"},
+			code:  codes.Unauthenticated,
+		},
+
+		{
+			types: []string{"Validation"},
+			code:  codes.InvalidArgument,
+		},
+
+		{
+			types: []string{"Timeout"},
+			code:  codes.DeadlineExceeded,
+		},
+
+		{
+			types: []string{"Throttled"},
+			code:  codes.ResourceExhausted,
+		},
+
+		{
+			types: []string{"Conflict"},
+			code:  codes.AlreadyExists,
+		},
+
+		{
+			types: nil,
+			code:  codes.Unknown,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.types), func(t *testing.T) {
+			err := New(errors.WithTypes(errors.New("oops"), test.types...))
+
+			s, ok := status.FromError(err)
+			if !ok {
+				t.Fatal("not a gRPC status error:", err)
+			}
+
+			if code := s.Code(); code != test.code {
+				t.Error("bad status code:")
+				t.Log("expected:", test.code)
+				t.Log("found:   ", code)
+			}
+
+			if msg := s.Message(); msg != "oops" {
+				t.Error("bad status message:", msg)
+			}
+		})
+	}
+}
+
+func TestNewStatusError(t *testing.T) {
+	err := status.Error(codes.NotFound, "oops")
+
+	if e := New(err); e != err {
+		t.Error("errors carrying a gRPC status must be returned unchanged:", e)
+	}
+
+	if e := New(errors.Adapt(err)); status.Code(e) != codes.NotFound {
+		t.Error("adapted errors must retain their gRPC status:", e)
+	}
+
+	if New(nil) != nil {
+		t.Error("New(nil) must return nil")
+	}
+}
@@ -0,0 +1,67 @@
+package grpcerrors
+
+import (
+	"context"
+	"io"
+
+	errors "github.com/segmentio/errors-go"
+	"google.golang.org/grpc"
+)
+
+// UnaryClientInterceptor returns a gRPC client interceptor which adapts the
+// errors returned by unary calls, so their types can be tested with errors.Is.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return adapt(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor returns a gRPC client interceptor which adapts the
+// errors returned when opening streams, and when sending or receiving messages
+// on those streams. The io.EOF errors marking the end of streams are returned
+// unchanged.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		s, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, adapt(err)
+		}
+		return &clientStream{ClientStream: s}, nil
+	}
+}
+
+// UnaryServerInterceptor returns a gRPC server interceptor which converts the
+// errors returned by unary handlers to gRPC status errors with New.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		res, err := handler(ctx, req)
+		return res, New(err)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC server interceptor which converts the
+// errors returned by stream handlers to gRPC status errors with New.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return New(handler(srv, ss))
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	return adapt(s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	return adapt(s.ClientStream.RecvMsg(m))
+}
+
+func adapt(err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+	return errors.Adapt(err)
+}
@@ -0,0 +1,125 @@
+package grpcerrors
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	intercept := UnaryClientInterceptor()
+
+	err := intercept(context.Background(), "/test/Method", nil, nil, nil,
+		func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+			return status.Error(codes.Unavailable, "oops")
+		},
+	)
+
+	if !errors.Is("Unavailable", err) || !errors.Is("Temporary", err) {
+		t.Error("bad error types:", errors.Types(err))
+	}
+
+	if code := status.Code(err); code != codes.Unavailable {
+		t.Error("the adapted error lost its gRPC status:", code)
+	}
+}
+
+type testClientStream struct {
+	grpc.ClientStream
+	err error
+}
+
+func (s *testClientStream) SendMsg(interface{}) error { return s.err }
+
+func (s *testClientStream) RecvMsg(interface{}) error { return s.err }
+
+func TestStreamClientInterceptor(t *testing.T) {
+	intercept := StreamClientInterceptor()
+
+	newStream := func(err error) grpc.Streamer {
+		return func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+			return &testClientStream{err: err}, nil
+		}
+	}
+
+	s, err := intercept(context.Background(), &grpc.StreamDesc{}, nil, "/test/Method", newStream(status.Error(codes.NotFound, "oops")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SendMsg(nil); !errors.Is("NotFound", err) {
+		t.Error("bad error types:", errors.Types(err))
+	}
+
+	if err := s.RecvMsg(nil); !errors.Is("NotFound", err) {
+		t.Error("bad error types:", errors.Types(err))
+	}
+
+	s, _ = intercept(context.Background(), &grpc.StreamDesc{}, nil, "/test/Method", newStream(io.EOF))
+
+	if err := s.RecvMsg(nil); err != io.EOF {
+		t.Error("io.EOF must be returned unchanged:", err)
+	}
+
+	_, err = intercept(context.Background(), &grpc.StreamDesc{}, nil, "/test/Method",
+		func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+			return nil, status.Error(codes.PermissionDenied, "oops")
+		},
+	)
+
+	if !errors.Is("PermissionDenied", err) {
+		t.Error("bad error types:", errors.Types(err))
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	intercept := UnaryServerInterceptor()
+
+	_, err := intercept(context.Background(), nil, &grpc.UnaryServerInfo{},
+		func(context.Context, interface{}) (interface{}, error) {
+			return nil, errors.WithTypes(errors.New("oops"), "Validation")
+		},
+	)
+
+	s, ok := status.FromError(err)
+	if !ok {
+		t.Fatal("not a gRPC status error:", err)
+	}
+
+	if s.Code() != codes.InvalidArgument {
+		t.Error("bad status code:", s.Code())
+	}
+
+	if s.Message() != "oops" {
+		t.Error("bad status message:", s.Message())
+	}
+
+	res, err := intercept(context.Background(), nil, &grpc.UnaryServerInfo{},
+		func(context.Context, interface{}) (interface{}, error) {
+			return "OK", nil
+		},
+	)
+
+	if res != "OK" || err != nil {
+		t.Error("bad response:", res, err)
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	intercept := StreamServerInterceptor()
+
+	err := intercept(nil, nil, &grpc.StreamServerInfo{},
+		func(interface{}, grpc.ServerStream) error {
+			return errors.WithTypes(errors.New("oops"), "Timeout")
+		},
+	)
+
+	if code := status.Code(err); code != codes.DeadlineExceeded {
+		t.Error("bad status code:", code)
+	}
+}
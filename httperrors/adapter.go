@@ -0,0 +1,50 @@
+package httperrors
+
+// Adapt checks whether err exposes a HTTP status code through a StatusCode()
+// int method and if it does, adapts it to carry the same types as errors
+// constructed from HTTP responses by this package, for example an error with
+// a status code of 503 will be of type "ServiceUnavailable" and "Temporary".
+//
+// This makes it possible to classify errors from other packages which embed a
+// HTTP status, like the awserr.RequestFailure errors of the AWS Go SDK.
+// Errors that were already classified by an adapter, which all expose the error
+// that they adapted with a Cause method, are returned unchanged.
+//
+// This function is automatically installed as a global adapter when importing
+// the httperrors package, a program likely should use errors.Adapt instead of
+// calling this adapter directly.
+func Adapt(err error) (error, bool) {
+	switch err.(type) {
+	case *statusError, *httpError:
+		return err, false
+	}
+
+	if _, ok := err.(interface{ Cause() error }); ok {
+		return err, false
+	}
+
+	if e, ok := err.(statusCoder); ok {
+		if code := e.StatusCode(); code > 0 {
+			return &statusError{
+				httpError: &httpError{code: code},
+				cause:     err,
+			}, true
+		}
+	}
+	return err, false
+}
+
+type statusCoder interface {
+	StatusCode() int
+}
+
+// statusError carries the types of a HTTP status code on an error which was
+// not constructed from a HTTP response.
+type statusError struct {
+	*httpError
+	cause error
+}
+
+func (e *statusError) Error() string   { return e.cause.Error() }
+func (e *statusError) Cause() error    { return e.cause }
+func (e *statusError) StatusCode() int { return e.code }
@@ -0,0 +1,61 @@
+package httperrors
+
+import (
+	"net/http"
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+	"github.com/segmentio/errors-go/errorstest"
+)
+
+func TestAdapt(t *testing.T) {
+	errorstest.TestAdapter(t, errors.AdapterFunc(Adapt),
+		errorstest.AdapterTest{
+			Error: &statusCodeError{code: http.StatusServiceUnavailable},
+			Types: []string{"ServiceUnavailable", "Temporary"},
+		},
+
+		errorstest.AdapterTest{
+			Error: &statusCodeError{code: http.StatusGatewayTimeout},
+			Types: []string{"GatewayTimeout", "Temporary", "Timeout"},
+		},
+
+		errorstest.AdapterTest{
+			Error: &statusCodeError{code: http.StatusForbidden},
+			Types: []string{"Forbidden", "PermissionDenied"},
+		},
+	)
+
+	if _, ok := Adapt(&statusCodeError{}); ok {
+		t.Error("errors with no status code must not be adapted")
+	}
+
+	if err := errors.Wrap(&statusCodeError{code: http.StatusServiceUnavailable}, "checkout"); !errors.Is("ServiceUnavailable", err) {
+		t.Error("errors with a status code must be adapted by the global adapter:", err)
+	}
+}
+
+func TestAdaptAdapted(t *testing.T) {
+	adapted, _ := Adapt(&statusCodeError{code: http.StatusServiceUnavailable})
+
+	for _, err := range []error{
+		adapted,
+		&httpError{code: http.StatusNotFound},
+		&classifiedError{statusCodeError{code: http.StatusNotFound}},
+	} {
+		if e, ok := Adapt(err); ok || e != err {
+			t.Errorf("%T was adapted again to %T", err, e)
+		}
+	}
+}
+
+type statusCodeError struct{ code int }
+
+func (e *statusCodeError) Error() string   { return http.StatusText(e.code) }
+func (e *statusCodeError) StatusCode() int { return e.code }
+
+// classifiedError mimics the output of adapters of other packages which expose
+// a status code, like the awserrors adapter.
+type classifiedError struct{ statusCodeError }
+
+func (e *classifiedError) Cause() error { return &e.statusCodeError }
@@ -0,0 +1,82 @@
+package httperrors
+
+import (
+	"strings"
+
+	errors "github.com/segmentio/errors-go"
+)
+
+// AuthFailure is an enumeration of the kinds of authentication or authorization
+// failures that an error may represent.
+type AuthFailure int
+
+const (
+	// AuthUnknown is returned for errors that are not authentication or
+	// authorization failures, or when the kind of failure is unknown.
+	AuthUnknown AuthFailure = iota
+
+	// AuthInvalidCredentials is returned when the credentials were rejected,
+	// the failure is permanent until the credentials are changed.
+	AuthInvalidCredentials
+
+	// AuthForbidden is returned when the credentials were accepted but the
+	// access to the resource was denied, the failure is permanent.
+	AuthForbidden
+
+	// AuthExpiredToken is returned when the token used to authenticate was
+	// rejected, the operation may be retried after refreshing the token.
+	AuthExpiredToken
+)
+
+// String satisfies the fmt.Stringer interface.
+func (f AuthFailure) String() string {
+	switch f {
+	case AuthInvalidCredentials:
+		return "InvalidCredentials"
+	case AuthForbidden:
+		return "Forbidden"
+	case AuthExpiredToken:
+		return "ExpiredToken"
+	default:
+		return "Unknown"
+	}
+}
+
+// AuthFailureKind returns the kind of authentication or authorization failure
+// that err represents.
+//
+// Errors of type "Forbidden" or "PermissionDenied" are AuthForbidden failures.
+// Errors of type "Unauthorized" or "Unauthenticated" are AuthExpiredToken
+// failures if they carry a "www-authenticate" tag with an "invalid_token" error
+// code (see RFC 6750), and AuthInvalidCredentials failures otherwise.
+//
+// Here is an example of a middleware retrying after refreshing its token:
+//
+//	switch httperrors.AuthFailureKind(err) {
+//	case httperrors.AuthExpiredToken:
+//		// refresh the token and retry
+//	case httperrors.AuthInvalidCredentials, httperrors.AuthForbidden:
+//		// fail
+//	}
+//
+func AuthFailureKind(err error) AuthFailure {
+	switch {
+	case errors.Is("Forbidden", err), errors.Is("PermissionDenied", err):
+		return AuthForbidden
+
+	case errors.Is("Unauthorized", err), errors.Is("Unauthenticated", err):
+		if isInvalidToken(errors.LookupTag(err, "www-authenticate")) {
+			return AuthExpiredToken
+		}
+		return AuthInvalidCredentials
+
+	default:
+		return AuthUnknown
+	}
+}
+
+func isInvalidToken(challenge string) bool {
+	challenge = strings.ToLower(challenge)
+	return strings.Contains(challenge, `error="invalid_token"`) ||
+		strings.Contains(challenge, "error=invalid_token")
+}
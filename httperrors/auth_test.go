@@ -0,0 +1,130 @@
+package httperrors
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+)
+
+func TestAuthFailureKind(t *testing.T) {
+	tests := []struct {
+		scenario string
+		code     int
+		status   string
+		header   string
+		kind     AuthFailure
+	}{
+		{
+			scenario: "401 without challenge",
+			code:     http.StatusUnauthorized,
+			status:   "401 Unauthorized",
+			kind:     AuthInvalidCredentials,
+		},
+
+		{
+			scenario: "401 with basic challenge",
+			code:     http.StatusUnauthorized,
+			status:   "401 Unauthorized",
+			header:   `Basic realm="example"`,
+			kind:     AuthInvalidCredentials,
+		},
+
+		{
+			scenario: "401 with invalid token challenge",
+			code:     http.StatusUnauthorized,
+			status:   "401 Unauthorized",
+			header:   `Bearer realm="example", error="invalid_token", error_description="The access token expired"`,
+			kind:     AuthExpiredToken,
+		},
+
+		{
+			scenario: "403",
+			code:     http.StatusForbidden,
+			status:   "403 Forbidden",
+			header:   `Bearer realm="example", error="insufficient_scope"`,
+			kind:     AuthForbidden,
+		},
+
+		{
+			scenario: "404",
+			code:     http.StatusNotFound,
+			status:   "404 Not Found",
+			kind:     AuthUnknown,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			res := &http.Response{
+				StatusCode: test.code,
+				Status:     test.status,
+				Header:     http.Header{},
+			}
+
+			if len(test.header) != 0 {
+				res.Header.Set("WWW-Authenticate", test.header)
+			}
+
+			err := errors.Wrap(New(res), "hello")
+
+			if kind := AuthFailureKind(err); kind != test.kind {
+				t.Error("bad auth failure kind:")
+				t.Log("expected:", test.kind)
+				t.Log("found:   ", kind)
+			}
+
+			if tag := errors.LookupTag(err, "www-authenticate"); tag != test.header {
+				t.Errorf("bad www-authenticate tag: %q", tag)
+			}
+		})
+	}
+
+	if kind := AuthFailureKind(nil); kind != AuthUnknown {
+		t.Error("nil errors must be of unknown auth failure kind:", kind)
+	}
+}
+
+func TestAuthenticateTagNameNormalized(t *testing.T) {
+	errors.SetTagNameNormalizer(func(name string) string {
+		return strings.Replace(name, "-", "_", -1)
+	})
+	defer errors.SetTagNameNormalizer(nil)
+
+	errors.RegisterHighCardinalityTags("www-authenticate")
+
+	err := New(&http.Response{
+		StatusCode: http.StatusUnauthorized,
+		Status:     "401 Unauthorized",
+		Header:     http.Header{"Www-Authenticate": {`Basic realm="example"`}},
+	})
+
+	if tags := errors.TagsMap(err); len(tags["www_authenticate"]) != 1 || len(tags["www-authenticate"]) != 0 {
+		t.Error("the name of the www-authenticate tag was not normalized:", tags)
+	}
+
+	if value := errors.LookupTag(err, "www-authenticate"); value != `Basic realm="example"` {
+		t.Errorf("bad value of the www-authenticate tag: %q", value)
+	}
+
+	if tags := errors.MetricTags(err); len(tags) != 0 {
+		t.Error("the www-authenticate tag must be excluded from metric tags:", tags)
+	}
+}
+
+func TestUnauthorizedGroup(t *testing.T) {
+	err := New(&http.Response{
+		StatusCode: http.StatusUnauthorized,
+		Status:     "401 Unauthorized",
+	})
+
+	if types := errors.ConcreteTypes(err); !reflect.DeepEqual(types, []string{"Unauthorized"}) {
+		t.Error("bad concrete types:", types)
+	}
+
+	if groups := errors.GroupByType(err); !reflect.DeepEqual(groups, map[string]int{"Unauthorized": 1}) {
+		t.Error("bad groups:", groups)
+	}
+}
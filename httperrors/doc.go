@@ -1,2 +1,6 @@
 // Package httperrors provides functions to construct errors from HTTP responses.
+//
+// Importing this package installs an adapter on the global set of adapters of
+// the parent errors-go package, which classifies errors exposing a StatusCode()
+// int method the same way as errors constructed from HTTP responses.
 package httperrors
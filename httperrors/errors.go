@@ -2,6 +2,7 @@ package httperrors
 
 import (
 	"net/http"
+	"time"
 
 	errors "github.com/segmentio/errors-go"
 )
@@ -15,9 +16,16 @@ import (
 // The type of the error is set to the status of the response, for example a 404
 // Not Found error will return an error of type "NotFound".
 //
-// It also may carry three other high-level types, "Temporary", "Timeout", and
-// "Throttled" which are deducted from the status of the response, for example
-// a 408 Request Timeout status will construct an error of type "Timeout".
+// It also may carry other high-level types, "Temporary", "Timeout", "Throttled",
+// "Validation", "PermissionDenied", and "Unauthenticated" which are deducted
+// from the status of the response, for example a 408 Request Timeout status
+// will construct an error of type "Timeout".
+//
+// If the response has a WWW-Authenticate header, the error is also tagged with
+// "www-authenticate" and the value of the header.
+//
+// If the response has a Retry-After header, the duration it carries is exposed
+// by the returned error and can be retrieved with errors.RetryAfter.
 //
 // Note that the response body is left untouched, so the program still has to
 // close it at some point.
@@ -69,39 +77,32 @@ func Wrap(res *http.Response, err error) (*http.Response, error) {
 }
 
 type httpError struct {
-	code   int
-	status string
-	method string
-	scheme string
-	host   string
-	path   string
-	tags   []errors.Tag
-	stack  errors.StackTrace
+	code       int
+	status     string
+	method     string
+	scheme     string
+	host       string
+	path       string
+	tags       []errors.Tag
+	stack      errors.StackTrace
+	retryAfter time.Duration
 }
 
 func newHTTPError(res *http.Response, stack errors.StackTrace) *httpError {
 	e := &httpError{
-		code:   res.StatusCode,
-		status: res.Status,
-		stack:  stack,
+		code:       res.StatusCode,
+		status:     res.Status,
+		stack:      stack,
+		retryAfter: errors.ParseRetryAfter(res.Header.Get("Retry-After")),
 	}
 
 	if req := res.Request; req != nil {
-		e.method = req.Method
-		e.scheme = req.URL.Scheme
-		e.host = req.URL.Host
-		e.path = req.URL.Path
-
-		if host := req.Header.Get("Host"); len(host) != 0 {
-			e.host = host
-		}
-
-		e.tags = []errors.Tag{
-			errors.T("method", e.method),
-			errors.T("scheme", e.scheme),
-			errors.T("host", e.host),
-			errors.T("path", e.path),
-		}
+		e.method, e.scheme, e.host, e.path = requestInfo(req)
+		e.tags = requestTags(e.method, e.scheme, e.host, e.path)
+	}
+
+	if auth := res.Header.Get("WWW-Authenticate"); len(auth) != 0 {
+		e.tags = append(e.tags, errors.T("www-authenticate", auth))
 	}
 
 	return e
@@ -139,6 +140,10 @@ func (e *httpError) StackTrace() errors.StackTrace {
 	return e.stack
 }
 
+func (e *httpError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
 func (e *httpError) Temporary() bool {
 	return e.Timeout() ||
 		e.Throttled() ||
@@ -148,6 +153,7 @@ func (e *httpError) Temporary() bool {
 		e.NotModified() ||
 		e.TemporaryRedirect() ||
 		e.NotFound() ||
+		e.TooEarly() ||
 		e.InternalServerError() ||
 		e.NotImplemented() ||
 		e.BadGateway() ||
@@ -163,10 +169,30 @@ func (e *httpError) Throttled() bool {
 	return e.TooManyRequests()
 }
 
+func (e *httpError) Validation() bool {
+	return e.BadRequest() ||
+		e.UnprocessableEntity() ||
+		e.LengthRequired() ||
+		e.PreconditionFailed() ||
+		e.RequestEntityTooLarge() ||
+		e.RequestURITooLong() ||
+		e.UnsupportedMediaType() ||
+		e.RequestedRangeNotSatisfiable()
+}
+
+func (e *httpError) PermissionDenied() bool {
+	return e.Forbidden()
+}
+
+func (e *httpError) Unauthenticated() bool {
+	return e.Unauthorized()
+}
+
 // 1xx
 func (e *httpError) Continue() bool           { return e.is(http.StatusContinue) }
 func (e *httpError) SwitchingProtocols() bool { return e.is(http.StatusSwitchingProtocols) }
 func (e *httpError) Processing() bool         { return e.is(http.StatusProcessing) }
+func (e *httpError) EarlyHints() bool         { return e.is(http.StatusEarlyHints) }
 
 // 2xx
 func (e *httpError) OK() bool                   { return e.is(http.StatusOK) }
@@ -212,9 +238,11 @@ func (e *httpError) RequestedRangeNotSatisfiable() bool {
 }
 func (e *httpError) ExpectationFailed() bool    { return e.is(http.StatusExpectationFailed) }
 func (e *httpError) Teapot() bool               { return e.is(http.StatusTeapot) }
+func (e *httpError) MisdirectedRequest() bool   { return e.is(http.StatusMisdirectedRequest) }
 func (e *httpError) UnprocessableEntity() bool  { return e.is(http.StatusUnprocessableEntity) }
 func (e *httpError) Locked() bool               { return e.is(http.StatusLocked) }
 func (e *httpError) FailedDependency() bool     { return e.is(http.StatusFailedDependency) }
+func (e *httpError) TooEarly() bool             { return e.is(http.StatusTooEarly) }
 func (e *httpError) UpgradeRequired() bool      { return e.is(http.StatusUpgradeRequired) }
 func (e *httpError) PreconditionRequired() bool { return e.is(http.StatusPreconditionRequired) }
 func (e *httpError) TooManyRequests() bool      { return e.is(http.StatusTooManyRequests) }
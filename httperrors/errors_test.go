@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"reflect"
 	"testing"
+	"time"
 
 	errors "github.com/segmentio/errors-go"
 )
@@ -31,6 +32,11 @@ func TestNew(t *testing.T) {
 			types: []string{"Processing"},
 		},
 
+		{
+			code:  http.StatusEarlyHints,
+			types: []string{"EarlyHints"},
+		},
+
 		{
 			code:  http.StatusOK,
 			types: []string{"OK"},
@@ -123,12 +129,12 @@ func TestNew(t *testing.T) {
 
 		{
 			code:  http.StatusBadRequest,
-			types: []string{"BadRequest"},
+			types: []string{"BadRequest", "Validation"},
 		},
 
 		{
 			code:  http.StatusUnauthorized,
-			types: []string{"Unauthorized"},
+			types: []string{"Unauthenticated", "Unauthorized"},
 		},
 
 		{
@@ -138,7 +144,7 @@ func TestNew(t *testing.T) {
 
 		{
 			code:  http.StatusForbidden,
-			types: []string{"Forbidden"},
+			types: []string{"Forbidden", "PermissionDenied"},
 		},
 
 		{
@@ -178,32 +184,32 @@ func TestNew(t *testing.T) {
 
 		{
 			code:  http.StatusLengthRequired,
-			types: []string{"LengthRequired"},
+			types: []string{"LengthRequired", "Validation"},
 		},
 
 		{
 			code:  http.StatusPreconditionFailed,
-			types: []string{"PreconditionFailed"},
+			types: []string{"PreconditionFailed", "Validation"},
 		},
 
 		{
 			code:  http.StatusRequestEntityTooLarge,
-			types: []string{"RequestEntityTooLarge"},
+			types: []string{"RequestEntityTooLarge", "Validation"},
 		},
 
 		{
 			code:  http.StatusRequestURITooLong,
-			types: []string{"RequestURITooLong"},
+			types: []string{"RequestURITooLong", "Validation"},
 		},
 
 		{
 			code:  http.StatusUnsupportedMediaType,
-			types: []string{"UnsupportedMediaType"},
+			types: []string{"UnsupportedMediaType", "Validation"},
 		},
 
 		{
 			code:  http.StatusRequestedRangeNotSatisfiable,
-			types: []string{"RequestedRangeNotSatisfiable"},
+			types: []string{"RequestedRangeNotSatisfiable", "Validation"},
 		},
 
 		{
@@ -216,9 +222,14 @@ func TestNew(t *testing.T) {
 			types: []string{"Teapot"},
 		},
 
+		{
+			code:  http.StatusMisdirectedRequest,
+			types: []string{"MisdirectedRequest"},
+		},
+
 		{
 			code:  http.StatusUnprocessableEntity,
-			types: []string{"UnprocessableEntity"},
+			types: []string{"UnprocessableEntity", "Validation"},
 		},
 
 		{
@@ -231,6 +242,11 @@ func TestNew(t *testing.T) {
 			types: []string{"FailedDependency"},
 		},
 
+		{
+			code:  http.StatusTooEarly,
+			types: []string{"Temporary", "TooEarly"},
+		},
+
 		{
 			code:  http.StatusUpgradeRequired,
 			types: []string{"UpgradeRequired"},
@@ -369,6 +385,93 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		header     string
+		retryAfter time.Duration
+	}{
+		{header: "", retryAfter: 0},
+		{header: "120", retryAfter: 2 * time.Minute},
+		{header: "-1", retryAfter: 0},
+		{header: "whatever", retryAfter: 0},
+		{header: "Wed, 21 Oct 2015 07:28:00 GMT", retryAfter: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.header, func(t *testing.T) {
+			err := New(&http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Status:     "503 Service Unavailable",
+				Header:     http.Header{"Retry-After": {test.header}},
+			})
+
+			d, ok := errors.RetryAfter(errors.Wrap(err, "hello"))
+
+			if d != test.retryAfter || ok != (test.retryAfter != 0) {
+				t.Error("bad retry-after duration:")
+				t.Log("expected:", test.retryAfter)
+				t.Log("found:   ", d, ok)
+			}
+		})
+	}
+
+	t.Run("http date", func(t *testing.T) {
+		now := time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC)
+		errors.SetClock(func() time.Time { return now })
+		defer errors.SetClock(nil)
+
+		err := New(&http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Status:     "429 Too Many Requests",
+			Header:     http.Header{"Retry-After": {now.Add(time.Hour).Format(http.TimeFormat)}},
+		})
+
+		if d, ok := errors.RetryAfter(err); !ok || d != time.Hour {
+			t.Error("bad retry-after duration:", d, ok)
+		}
+	})
+}
+
+func TestConcreteTypes(t *testing.T) {
+	err := New(&http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Status:     "503 Service Unavailable",
+	})
+
+	if types := errors.Types(err); !reflect.DeepEqual(types, []string{"ServiceUnavailable", "Temporary"}) {
+		t.Error("bad error types:", types)
+	}
+
+	if types := errors.ConcreteTypes(err); !reflect.DeepEqual(types, []string{"ServiceUnavailable"}) {
+		t.Error("bad concrete error types:", types)
+	}
+}
+
+func TestTypesIntersect(t *testing.T) {
+	err := errors.Wrap(New(&http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Status:     "429 Too Many Requests",
+	}), "hello")
+
+	candidates := []string{"Timeout", "Throttled", "TooManyRequests", "Throttled"}
+
+	if types := errors.TypesIntersect(err, candidates); !reflect.DeepEqual(types, []string{"Throttled", "TooManyRequests"}) {
+		t.Error("bad types intersection:", types)
+	}
+
+	if !errors.IsAnyOf(err, candidates) {
+		t.Error("the error was expected to have one of the candidate types")
+	}
+
+	if errors.IsAnyOf(err, []string{"NotFound", "Timeout"}) {
+		t.Error("the error was not expected to have one of the candidate types")
+	}
+
+	if types := errors.TypesIntersect(nil, candidates); types != nil {
+		t.Error("unexpected types intersection of a nil error:", types)
+	}
+}
+
 func TestWrap(t *testing.T) {
 	t.Run("error", testWrapError)
 	t.Run("200", testWrap200)
@@ -0,0 +1,50 @@
+package httperrors
+
+import (
+	"log"
+	"net/http"
+
+	errors "github.com/segmentio/errors-go"
+)
+
+// Recover returns a http handler which calls next and recovers from the panics
+// it may raise. The panic values are converted to errors which carry the stack
+// trace of the panic, the tags of the request (see WithRequest), and the type
+// "InternalServerError". The errors are logged with their full stack trace and
+// a 500 Internal Server Error response is written to the client.
+//
+// Panics with http.ErrAbortHandler are propagated so the http server can abort
+// the response.
+func Recover(next http.Handler) http.Handler {
+	return RecoverWith(next, nil)
+}
+
+// RecoverWith is similar to Recover but calls handle with the errors converted
+// from panics instead of logging them and writing a 500 response, which lets
+// programs customize how the errors are reported and rendered. If handle is
+// nil, the function behaves like Recover.
+func RecoverWith(next http.Handler, handle func(http.ResponseWriter, *http.Request, error)) http.Handler {
+	if handle == nil {
+		handle = handleRecovered
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			err := errors.ErrWithStack(v)
+			err = errors.WithTypes(WithRequest(err, r), "InternalServerError")
+			handle(w, r, err)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func handleRecovered(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("%+v", err)
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
@@ -0,0 +1,91 @@
+package httperrors
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+)
+
+func TestRecover(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log.SetOutput(buf)
+	defer log.SetOutput(os.Stderr)
+
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("oops")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Error("bad status code:", w.Code)
+	}
+
+	if s := buf.String(); !strings.Contains(s, "oops") || !strings.Contains(s, "TestRecover") {
+		t.Errorf("the error was not logged with its stack trace: %q", s)
+	}
+}
+
+func TestRecoverWith(t *testing.T) {
+	var recovered error
+
+	h := RecoverWith(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(errors.NewNoStack("oops"))
+	}), func(w http.ResponseWriter, r *http.Request, err error) {
+		recovered = err
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Error("bad status code:", w.Code)
+	}
+
+	if recovered == nil {
+		t.Fatal("the hook was not called")
+	}
+
+	if msg := recovered.Error(); msg != "oops" {
+		t.Error("bad error message:", msg)
+	}
+
+	if !errors.Is("InternalServerError", recovered) {
+		t.Error("the error is not of type InternalServerError:", errors.Types(recovered))
+	}
+
+	if !errors.HasAnyStack(recovered) {
+		t.Error("the error has no stack trace")
+	}
+
+	if !strings.Contains(fmt.Sprintf("%+v", recovered), "TestRecoverWith") {
+		t.Error("the stack trace does not include the function that panicked")
+	}
+
+	if tags := errors.TagsMap(recovered); len(tags["method"]) != 1 || tags["method"][0] != "POST" {
+		t.Error("the error was not tagged with the request:", tags)
+	}
+}
+
+func TestRecoverAbortHandler(t *testing.T) {
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Error("http.ErrAbortHandler was not propagated:", v)
+		}
+	}()
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,84 @@
+package httperrors
+
+import (
+	"net/http"
+
+	errors "github.com/segmentio/errors-go"
+)
+
+// WithRequest returns an error that wraps err and is tagged with the method,
+// scheme, host, and path of req, using the same tags as the errors returned by
+// New. If the request has a X-Request-ID header, the error is also tagged with
+// "x-request-id" and the value of the header.
+//
+// The function is intended to be used by HTTP handlers to attach the context
+// of the request they are serving to errors that did not originate from a HTTP
+// response, for example:
+//
+//	func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+//		if err := h.serve(w, r); err != nil {
+//			log.Printf("%v", httperrors.WithRequest(err, r))
+//		}
+//	}
+//
+// If err or req is nil the function returns err.
+func WithRequest(err error, req *http.Request) error {
+	if err == nil || req == nil {
+		return err
+	}
+
+	tags := requestTags(serverRequestInfo(req))
+
+	if id := req.Header.Get("X-Request-ID"); len(id) != 0 {
+		tags = append(tags, errors.T("x-request-id", id))
+	}
+
+	return errors.WithTags(err, tags...)
+}
+
+// requestInfo returns the method, scheme, host, and path of req, taken from
+// the request URL, which is set on client requests. The Host header takes
+// precedence over the host of the URL.
+func requestInfo(req *http.Request) (method, scheme, host, path string) {
+	method = req.Method
+
+	if u := req.URL; u != nil {
+		scheme, host, path = u.Scheme, u.Host, u.Path
+	}
+
+	if h := req.Header.Get("Host"); len(h) != 0 {
+		host = h
+	}
+
+	return
+}
+
+// serverRequestInfo is like requestInfo, but when the request URL has no scheme
+// or host, which is the case for server requests, they are deduced from the TLS
+// state and Host field of the request.
+func serverRequestInfo(req *http.Request) (method, scheme, host, path string) {
+	method, scheme, host, path = requestInfo(req)
+
+	if len(scheme) == 0 {
+		if req.TLS != nil {
+			scheme = "https"
+		} else {
+			scheme = "http"
+		}
+	}
+
+	if len(host) == 0 {
+		host = req.Host
+	}
+
+	return
+}
+
+func requestTags(method, scheme, host, path string) []errors.Tag {
+	return []errors.Tag{
+		errors.T("method", method),
+		errors.T("scheme", scheme),
+		errors.T("host", host),
+		errors.T("path", path),
+	}
+}
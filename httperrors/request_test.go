@@ -0,0 +1,112 @@
+package httperrors
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+)
+
+func TestWithRequest(t *testing.T) {
+	tests := []struct {
+		scenario string
+		request  func() *http.Request
+		tags     []errors.Tag
+	}{
+		{
+			scenario: "client request",
+			request: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPost, "https://localhost:443/api", nil)
+				req.Header.Set("Host", "localhost")
+				return req
+			},
+			tags: []errors.Tag{
+				errors.T("host", "localhost"),
+				errors.T("method", "POST"),
+				errors.T("path", "/api"),
+				errors.T("scheme", "https"),
+			},
+		},
+
+		{
+			scenario: "server request",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+				req.Header.Set("X-Request-ID", "1234")
+				return req
+			},
+			tags: []errors.Tag{
+				errors.T("host", "example.com"),
+				errors.T("method", "GET"),
+				errors.T("path", "/users/42"),
+				errors.T("scheme", "http"),
+				errors.T("x-request-id", "1234"),
+			},
+		},
+
+		{
+			scenario: "server request over tls",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodDelete, "/", nil)
+				req.Host = "localhost:4242"
+				req.TLS = &tls.ConnectionState{}
+				return req
+			},
+			tags: []errors.Tag{
+				errors.T("host", "localhost:4242"),
+				errors.T("method", "DELETE"),
+				errors.T("path", "/"),
+				errors.T("scheme", "https"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			cause := errors.New("oops")
+			err := WithRequest(cause, test.request())
+
+			if errors.Cause(err) != errors.Cause(cause) {
+				t.Error("bad error cause:", err)
+			}
+
+			if tags := errors.Tags(err); !reflect.DeepEqual(tags, test.tags) {
+				t.Error("error tags mismatch:")
+				t.Log("expected:", test.tags)
+				t.Log("found:   ", tags)
+			}
+		})
+	}
+
+	if err := WithRequest(nil, httptest.NewRequest(http.MethodGet, "/", nil)); err != nil {
+		t.Error("WithRequest must return nil when the error is nil:", err)
+	}
+}
+
+func TestNewWithServerRequest(t *testing.T) {
+	// The defaults applied by WithRequest to server requests must not change
+	// the tags of errors constructed from responses.
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+
+	err := New(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Status:     http.StatusText(http.StatusNotFound),
+		Request:    req,
+	})
+
+	tags := []errors.Tag{
+		errors.T("host", ""),
+		errors.T("method", "GET"),
+		errors.T("path", "/users/42"),
+		errors.T("scheme", ""),
+	}
+
+	if errTags := errors.Tags(err); !reflect.DeepEqual(errTags, tags) {
+		t.Error("error tags mismatch:")
+		t.Log("expected:", tags)
+		t.Log("found:   ", errTags)
+	}
+}
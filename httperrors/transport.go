@@ -0,0 +1,78 @@
+package httperrors
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	errors "github.com/segmentio/errors-go"
+)
+
+// maxDrainBytes is the maximum number of bytes read from the body of responses
+// converted to errors, so the connection can be reused without downloading
+// arbitrarily large bodies.
+const maxDrainBytes = 64 * 1024
+
+// Transport wraps next in a http.RoundTripper which converts responses with a
+// status code equal or greater than 400 to errors. If next is nil,
+// http.DefaultTransport is used.
+//
+// The function makes it possible to centralize the conversion of responses to
+// errors for all requests sent by a http client:
+//
+//	client := &http.Client{
+//		Transport: httperrors.Transport(http.DefaultTransport),
+//	}
+//
+// Note that the http.RoundTripper interface requires that errors are only
+// returned when no response was received, the returned transport deviates from
+// this contract on purpose. The body of responses converted to errors is
+// drained and closed, so the connection can be reused.
+//
+// The threshold of 400 is chosen on purpose rather than 300, so responses with
+// a 3xx status code are not converted to errors and http.Client can still
+// follow redirects.
+//
+// Note that http.Client wraps the errors returned by its transport in values of
+// type *url.Error, the errors constructed by the transport are exposed in the
+// Err field.
+func Transport(next http.RoundTripper) http.RoundTripper {
+	return TransportWithThreshold(next, http.StatusBadRequest)
+}
+
+// TransportWithThreshold is similar to Transport but only converts responses
+// with a status code equal or greater than threshold to errors. With a threshold
+// of 300 or less, redirects are converted to errors as well, which prevents
+// http.Client from following them.
+func TransportWithThreshold(next http.RoundTripper, threshold int) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{
+		next:      next,
+		threshold: threshold,
+	}
+}
+
+type transport struct {
+	next      http.RoundTripper
+	threshold int
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, errors.WithStackTrace(err, errors.CaptureStackTrace(1))
+	}
+
+	if res.StatusCode >= t.threshold {
+		io.Copy(ioutil.Discard, io.LimitReader(res.Body, maxDrainBytes))
+		res.Body.Close()
+		if res.Request == nil {
+			res.Request = req
+		}
+		return nil, newHTTPError(res, errors.CaptureStackTrace(1))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,161 @@
+package httperrors
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func stubTransport(code int, body *closeRecorder) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: code,
+			Status:     http.StatusText(code),
+			Body:       body,
+			Request:    req,
+		}, nil
+	})
+}
+
+func TestTransport(t *testing.T) {
+	t.Run("responses below the threshold are returned", func(t *testing.T) {
+		body := &closeRecorder{Reader: strings.NewReader("Hello World!")}
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+
+		res, err := Transport(stubTransport(http.StatusOK, body)).RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != http.StatusOK {
+			t.Error("bad status code:", res.StatusCode)
+		}
+
+		if body.closed {
+			t.Error("the response body must not be closed")
+		}
+	})
+
+	t.Run("responses above the threshold are converted to errors", func(t *testing.T) {
+		body := &closeRecorder{Reader: strings.NewReader("Internal Server Error")}
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+
+		res, err := Transport(stubTransport(http.StatusInternalServerError, body)).RoundTrip(req)
+		if res != nil {
+			t.Error("unexpected response:", res)
+		}
+
+		if !errors.Is("InternalServerError", err) {
+			t.Error("bad error types:", errors.Types(err))
+		}
+
+		if !body.closed {
+			t.Error("the response body was not closed")
+		}
+
+		if n, _ := body.Read(make([]byte, 1)); n != 0 {
+			t.Error("the response body was not drained")
+		}
+
+		tags := []errors.Tag{
+			errors.T("host", "localhost"),
+			errors.T("method", "GET"),
+			errors.T("path", "/"),
+			errors.T("scheme", "http"),
+		}
+
+		if errTags := errors.Tags(err); !reflect.DeepEqual(errTags, tags) {
+			t.Error("error tags mismatch:")
+			t.Log("expected:", tags)
+			t.Log("found:   ", errTags)
+		}
+	})
+
+	t.Run("redirects are not converted to errors by default", func(t *testing.T) {
+		body := &closeRecorder{Reader: strings.NewReader("")}
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+
+		res, err := Transport(stubTransport(http.StatusFound, body)).RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != http.StatusFound {
+			t.Error("bad status code:", res.StatusCode)
+		}
+	})
+
+	t.Run("redirects are converted to errors when the threshold is 300", func(t *testing.T) {
+		body := &closeRecorder{Reader: strings.NewReader("")}
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+
+		res, err := TransportWithThreshold(stubTransport(http.StatusFound, body), http.StatusMultipleChoices).RoundTrip(req)
+		if res != nil {
+			t.Error("unexpected response:", res)
+		}
+
+		if !errors.Is("Found", err) {
+			t.Error("bad error types:", errors.Types(err))
+		}
+
+		if !body.closed {
+			t.Error("the response body was not closed")
+		}
+	})
+
+	t.Run("client errors are converted to errors by default", func(t *testing.T) {
+		body := &closeRecorder{Reader: strings.NewReader("")}
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+
+		if _, err := Transport(stubTransport(http.StatusNotFound, body)).RoundTrip(req); !errors.Is("NotFound", err) {
+			t.Error("bad error types:", errors.Types(err))
+		}
+	})
+
+	t.Run("the threshold is configurable", func(t *testing.T) {
+		body := &closeRecorder{Reader: strings.NewReader("")}
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+
+		res, err := TransportWithThreshold(stubTransport(http.StatusNotFound, body), 500).RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StatusCode != http.StatusNotFound {
+			t.Error("bad status code:", res.StatusCode)
+		}
+	})
+
+	t.Run("transport errors are returned", func(t *testing.T) {
+		cause := errors.New("connection refused")
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+
+		_, err := Transport(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, cause
+		})).RoundTrip(req)
+
+		if errors.Cause(err) != errors.Cause(cause) {
+			t.Error("bad error cause:", err)
+		}
+	})
+}
@@ -1,10 +1,13 @@
 package ioerrors
 
-import "io"
+import (
+	"bufio"
+	"io"
+)
 
 // Adapt checks the type of err and if it matches one of the error types or one
-// of the error values of the standard io package, adapts it to make error types
-// discoverable using the errors.Is function.
+// of the error values of the standard io, bufio, and io/fs packages, adapts it to
+// make error types discoverable using the errors.Is function.
 //
 // This function is automatically installed as a global adapter when importing
 // the neterrors package, a program likely should use errors.Adapt instead of
@@ -29,8 +32,14 @@ func Adapt(err error) (error, bool) {
 	case io.ErrUnexpectedEOF:
 		return &unexpectedEOF{err}, true
 
+	case bufio.ErrBufferFull:
+		return &bufferFull{err}, true
+
+	case bufio.ErrTooLong:
+		return &tooLong{err}, true
+
 	default:
-		return err, false
+		return adaptFS(err)
 	}
 }
 
@@ -69,3 +78,16 @@ type unexpectedEOF struct{ cause error }
 func (e *unexpectedEOF) Error() string       { return e.cause.Error() }
 func (e *unexpectedEOF) Cause() error        { return e.cause }
 func (e *unexpectedEOF) UnexpectedEOF() bool { return true }
+
+type bufferFull struct{ cause error }
+
+func (e *bufferFull) Error() string     { return e.cause.Error() }
+func (e *bufferFull) Cause() error      { return e.cause }
+func (e *bufferFull) BufferFull() bool  { return true }
+func (e *bufferFull) ShortBuffer() bool { return true }
+
+type tooLong struct{ cause error }
+
+func (e *tooLong) Error() string { return e.cause.Error() }
+func (e *tooLong) Cause() error  { return e.cause }
+func (e *tooLong) TooLong() bool { return true }
@@ -1,6 +1,7 @@
 package ioerrors
 
 import (
+	"bufio"
 	"io"
 	"testing"
 
@@ -39,5 +40,15 @@ func TestAdapt(t *testing.T) {
 			Error: io.ErrUnexpectedEOF,
 			Types: []string{"UnexpectedEOF"},
 		},
+
+		errorstest.AdapterTest{
+			Error: bufio.ErrBufferFull,
+			Types: []string{"BufferFull", "ShortBuffer"},
+		},
+
+		errorstest.AdapterTest{
+			Error: bufio.ErrTooLong,
+			Types: []string{"TooLong"},
+		},
 	)
 }
@@ -1,5 +1,5 @@
-// Package ioerrors provides adapters for errors generated by the standard io
-// package.
+// Package ioerrors provides adapters for errors generated by the standard io,
+// bufio, and io/fs packages.
 //
 // Importing this package installs the io errors adapters on the global set of
 // adapters of the parent errors=go package.
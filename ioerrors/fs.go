@@ -0,0 +1,76 @@
+//go:build go1.20
+// +build go1.20
+
+package ioerrors
+
+import "io/fs"
+
+func adaptFS(err error) (error, bool) {
+	switch err {
+	case fs.ErrInvalid:
+		return &invalid{err}, true
+
+	case fs.ErrPermission:
+		return &permission{err}, true
+
+	case fs.ErrExist:
+		return &exist{err}, true
+
+	case fs.ErrNotExist:
+		return &notExist{err}, true
+
+	case fs.ErrClosed:
+		return &closed{err}, true
+
+	case fs.SkipDir:
+		return &skipDir{err}, true
+
+	case fs.SkipAll:
+		return &skipAll{err}, true
+
+	default:
+		return err, false
+	}
+}
+
+type invalid struct{ cause error }
+
+func (e *invalid) Error() string    { return e.cause.Error() }
+func (e *invalid) Cause() error     { return e.cause }
+func (e *invalid) Validation() bool { return true }
+
+type permission struct{ cause error }
+
+func (e *permission) Error() string          { return e.cause.Error() }
+func (e *permission) Cause() error           { return e.cause }
+func (e *permission) PermissionDenied() bool { return true }
+
+type exist struct{ cause error }
+
+func (e *exist) Error() string       { return e.cause.Error() }
+func (e *exist) Cause() error        { return e.cause }
+func (e *exist) AlreadyExists() bool { return true }
+
+type notExist struct{ cause error }
+
+func (e *notExist) Error() string  { return e.cause.Error() }
+func (e *notExist) Cause() error   { return e.cause }
+func (e *notExist) NotFound() bool { return true }
+
+type closed struct{ cause error }
+
+func (e *closed) Error() string { return e.cause.Error() }
+func (e *closed) Cause() error  { return e.cause }
+func (e *closed) Closed() bool  { return true }
+
+type skipDir struct{ cause error }
+
+func (e *skipDir) Error() string { return e.cause.Error() }
+func (e *skipDir) Cause() error  { return e.cause }
+func (e *skipDir) SkipDir() bool { return true }
+
+type skipAll struct{ cause error }
+
+func (e *skipAll) Error() string { return e.cause.Error() }
+func (e *skipAll) Cause() error  { return e.cause }
+func (e *skipAll) SkipAll() bool { return true }
@@ -0,0 +1,8 @@
+//go:build !go1.20
+// +build !go1.20
+
+package ioerrors
+
+func adaptFS(err error) (error, bool) {
+	return err, false
+}
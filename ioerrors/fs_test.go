@@ -0,0 +1,51 @@
+//go:build go1.20
+// +build go1.20
+
+package ioerrors
+
+import (
+	"io/fs"
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+	"github.com/segmentio/errors-go/errorstest"
+)
+
+func TestAdaptFS(t *testing.T) {
+	errorstest.TestAdapter(t, errors.AdapterFunc(Adapt),
+		errorstest.AdapterTest{
+			Error: fs.ErrInvalid,
+			Types: []string{"Validation"},
+		},
+
+		errorstest.AdapterTest{
+			Error: fs.ErrPermission,
+			Types: []string{"PermissionDenied"},
+		},
+
+		errorstest.AdapterTest{
+			Error: fs.ErrExist,
+			Types: []string{"AlreadyExists"},
+		},
+
+		errorstest.AdapterTest{
+			Error: fs.ErrNotExist,
+			Types: []string{"NotFound"},
+		},
+
+		errorstest.AdapterTest{
+			Error: fs.ErrClosed,
+			Types: []string{"Closed"},
+		},
+
+		errorstest.AdapterTest{
+			Error: fs.SkipDir,
+			Types: []string{"SkipDir"},
+		},
+
+		errorstest.AdapterTest{
+			Error: fs.SkipAll,
+			Types: []string{"SkipAll"},
+		},
+	)
+}
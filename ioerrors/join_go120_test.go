@@ -0,0 +1,24 @@
+//go:build go1.20
+// +build go1.20
+
+package ioerrors
+
+import (
+	stderrors "errors"
+	"io"
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+)
+
+func TestJoinStdlibIs(t *testing.T) {
+	err := errors.Join(io.EOF, errors.New("A"))
+
+	if !errors.Is("EOF", err) {
+		t.Error("the causes of joined errors must be adapted")
+	}
+
+	if !stderrors.Is(err, io.EOF) {
+		t.Error("errors.Is of the standard library must find the adapted causes of joined errors")
+	}
+}
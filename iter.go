@@ -0,0 +1,19 @@
+//go:build go1.23
+// +build go1.23
+
+package errors
+
+import "iter"
+
+// All returns an iterator over the causes of err, yielding the same errors as
+// Range. The iteration can be stopped early by breaking out of the loop:
+//
+//	for cause := range errors.All(err) {
+//		if errors.Is("Timeout", cause) {
+//			break
+//		}
+//	}
+//
+func All(err error) iter.Seq[error] {
+	return func(yield func(error) bool) { Range(err, yield) }
+}
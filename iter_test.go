@@ -0,0 +1,32 @@
+//go:build go1.23
+// +build go1.23
+
+package errors
+
+import "testing"
+
+func TestAll(t *testing.T) {
+	a, b, c := New("A"), New("B"), New("C")
+	err := Wrap(Join(a, b, c), "batch failed")
+
+	var found []error
+	for cause := range All(err) {
+		found = append(found, cause)
+	}
+
+	if len(found) != 3 || found[0] != a || found[1] != b || found[2] != c {
+		t.Error("bad causes:", found)
+	}
+
+	found = found[:0]
+	for cause := range All(err) {
+		found = append(found, cause)
+		if cause == b {
+			break
+		}
+	}
+
+	if len(found) != 2 {
+		t.Error("the iteration did not stop early:", found)
+	}
+}
@@ -0,0 +1,44 @@
+//go:build go1.20
+// +build go1.20
+
+package errors
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJoinStdlibCompatibility(t *testing.T) {
+	errs := []error{io.EOF, nil, errors.New("A"), &domainError{}}
+
+	std := errors.Join(errs...).(interface{ Unwrap() []error })
+	ours, ok := Join(errs...).(interface{ Unwrap() []error })
+
+	if !ok {
+		t.Fatal("the error returned by Join has no Unwrap() []error method")
+	}
+
+	if u1, u2 := std.Unwrap(), ours.Unwrap(); !reflect.DeepEqual(u1, u2) {
+		t.Error("Unwrap does not return the same errors as the standard library:")
+		t.Log("expected:", u1)
+		t.Log("found:   ", u2)
+	}
+
+	if s1, s2 := strings.Replace(std.(error).Error(), "\n", "; ", -1), ours.(error).Error(); s1 != s2 {
+		t.Error("messages of the joined errors must be separated by semicolons instead of newlines:")
+		t.Logf("expected: %q", s1)
+		t.Logf("found:    %q", s2)
+	}
+
+	if !errors.Is(ours.(error), io.EOF) {
+		t.Error("errors.Is of the standard library must find the causes of joined errors")
+	}
+
+	var e *domainError
+	if !errors.As(ours.(error), &e) {
+		t.Error("errors.As of the standard library must find the causes of joined errors")
+	}
+}
@@ -0,0 +1,68 @@
+package jsonerrors
+
+import (
+	"encoding/json"
+
+	errors "github.com/segmentio/errors-go"
+)
+
+// Adapt checks the type of err and if it matches one of the error types of the
+// standard encoding/json package, adapts it to be of type "Validation", since
+// those errors are caused by malformed or unexpected inputs.
+//
+// Syntax errors are tagged with the "offset" at which the error occurred, and
+// unmarshal type errors are tagged with the "field" and "type" that the value
+// could not be decoded into.
+//
+// This function is automatically installed as a global adapter when importing
+// the jsonerrors package, a program likely should use errors.Adapt instead of
+// calling this adapter directly.
+func Adapt(err error) (error, bool) {
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		return &syntaxError{e}, true
+
+	case *json.UnmarshalTypeError:
+		return &unmarshalTypeError{e}, true
+
+	case *json.UnsupportedTypeError:
+		return &validation{e}, true
+	}
+	return err, false
+}
+
+type syntaxError struct{ cause *json.SyntaxError }
+
+func (e *syntaxError) Cause() error     { return e.cause }
+func (e *syntaxError) Error() string    { return e.cause.Error() }
+func (e *syntaxError) Validation() bool { return true }
+
+func (e *syntaxError) Tags() []errors.Tag {
+	return []errors.Tag{errors.TInt("offset", e.cause.Offset)}
+}
+
+type unmarshalTypeError struct{ cause *json.UnmarshalTypeError }
+
+func (e *unmarshalTypeError) Cause() error     { return e.cause }
+func (e *unmarshalTypeError) Error() string    { return e.cause.Error() }
+func (e *unmarshalTypeError) Validation() bool { return true }
+
+func (e *unmarshalTypeError) Tags() []errors.Tag {
+	tags := make([]errors.Tag, 0, 2)
+
+	if len(e.cause.Field) != 0 {
+		tags = append(tags, errors.T("field", e.cause.Field))
+	}
+
+	if e.cause.Type != nil {
+		tags = append(tags, errors.T("type", e.cause.Type.String()))
+	}
+
+	return tags
+}
+
+type validation struct{ cause error }
+
+func (e *validation) Cause() error     { return e.cause }
+func (e *validation) Error() string    { return e.cause.Error() }
+func (e *validation) Validation() bool { return true }
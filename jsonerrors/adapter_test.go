@@ -0,0 +1,53 @@
+package jsonerrors
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+	"github.com/segmentio/errors-go/errorstest"
+)
+
+func TestAdapt(t *testing.T) {
+	var v struct {
+		Name string `json:"name"`
+	}
+
+	syntaxError := json.Unmarshal([]byte(`{"name":}`), &v)
+	unmarshalTypeError := json.Unmarshal([]byte(`{"name":42}`), &v)
+	_, unsupportedTypeError := json.Marshal(make(chan int))
+
+	errorstest.TestAdapter(t, errors.AdapterFunc(Adapt),
+		errorstest.AdapterTest{
+			Error: syntaxError,
+			Types: []string{"Validation"},
+			Tags:  []errors.Tag{errors.T("offset", "9")},
+		},
+
+		errorstest.AdapterTest{
+			Error: unmarshalTypeError,
+			Types: []string{"Validation"},
+			Tags: []errors.Tag{
+				errors.T("field", "name"),
+				errors.T("type", "string"),
+			},
+		},
+
+		errorstest.AdapterTest{
+			Error: unsupportedTypeError,
+			Types: []string{"Validation"},
+		},
+	)
+}
+
+func TestAdaptUnmarshalTypeErrorWithoutField(t *testing.T) {
+	err := &json.UnmarshalTypeError{
+		Value: "number",
+		Type:  reflect.TypeOf(""),
+	}
+
+	if tags := errors.Tags(errors.Adapt(err)); !reflect.DeepEqual(tags, []errors.Tag{errors.T("type", "string")}) {
+		t.Error("bad tags:", tags)
+	}
+}
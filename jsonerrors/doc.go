@@ -0,0 +1,6 @@
+// Package jsonerrors provides adapters for errors generated by the standard
+// encoding/json package.
+//
+// Importing this package installs the json errors adapters on the global set
+// of adapters of the parent errors-go package.
+package jsonerrors
@@ -0,0 +1,7 @@
+package jsonerrors
+
+import errors "github.com/segmentio/errors-go"
+
+func init() {
+	errors.Register(errors.AdapterFunc(Adapt))
+}
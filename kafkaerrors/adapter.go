@@ -0,0 +1,58 @@
+package kafkaerrors
+
+import (
+	errors "github.com/segmentio/errors-go"
+	"github.com/segmentio/kafka-go"
+)
+
+// Adapt checks the type of err is an error code returned by kafka brokers, and
+// adapts it to make error types discoverable using the errors.Is function. The
+// Temporary and Timeout types are the ones reported by the kafka.Error value,
+// other types are derived from the error code.
+//
+// This function is automatically installed as a global adapter when importing
+// the kafkaerrors package, a program likely should use errors.Adapt instead of
+// calling this adapter directly.
+func Adapt(err error) (error, bool) {
+	if e, ok := err.(kafka.Error); ok {
+		return &kafkaError{cause: e}, true
+	}
+	return err, false
+}
+
+type kafkaError struct {
+	cause kafka.Error
+}
+
+func (e *kafkaError) Cause() error { return e.cause }
+
+func (e *kafkaError) Error() string { return e.cause.Error() }
+
+// Code returns the numeric code of the kafka error.
+func (e *kafkaError) Code() int { return int(e.cause) }
+
+func (e *kafkaError) Tags() []errors.Tag {
+	return []errors.Tag{errors.TInt("kafka_code", int64(e.cause))}
+}
+
+// Kafka-specific error types
+
+func (e *kafkaError) LeaderNotAvailable() bool { return e.cause == kafka.LeaderNotAvailable }
+
+func (e *kafkaError) NotLeaderForPartition() bool { return e.cause == kafka.NotLeaderForPartition }
+
+func (e *kafkaError) OffsetOutOfRange() bool { return e.cause == kafka.OffsetOutOfRange }
+
+// Common error types
+
+func (e *kafkaError) OutOfRange() bool { return e.OffsetOutOfRange() }
+
+func (e *kafkaError) Validation() bool { return e.OffsetOutOfRange() }
+
+func (e *kafkaError) Temporary() bool {
+	return e.cause.Temporary() ||
+		e.LeaderNotAvailable() ||
+		e.NotLeaderForPartition()
+}
+
+func (e *kafkaError) Timeout() bool { return e.cause.Timeout() }
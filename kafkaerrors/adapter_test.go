@@ -0,0 +1,76 @@
+package kafkaerrors
+
+import (
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+	"github.com/segmentio/errors-go/errorstest"
+	_ "github.com/segmentio/errors-go/neterrors"
+	"github.com/segmentio/kafka-go"
+)
+
+func TestAdapt(t *testing.T) {
+	errorstest.TestAdapter(t, errors.AdapterFunc(Adapt),
+		errorstest.AdapterTest{
+			Error: kafka.InvalidTopic,
+			Types: []string{},
+			Tags:  []errors.Tag{{Name: "kafka_code", Value: "17"}},
+		},
+
+		errorstest.AdapterTest{
+			Error: kafka.OffsetOutOfRange,
+			Types: []string{"OffsetOutOfRange", "OutOfRange", "Validation"},
+			Tags:  []errors.Tag{{Name: "kafka_code", Value: "1"}},
+		},
+
+		errorstest.AdapterTest{
+			Error: kafka.LeaderNotAvailable,
+			Types: []string{"LeaderNotAvailable", "Temporary"},
+			Tags:  []errors.Tag{{Name: "kafka_code", Value: "5"}},
+		},
+
+		errorstest.AdapterTest{
+			Error: kafka.NotLeaderForPartition,
+			Types: []string{"NotLeaderForPartition", "Temporary"},
+			Tags:  []errors.Tag{{Name: "kafka_code", Value: "6"}},
+		},
+
+		errorstest.AdapterTest{
+			Error: kafka.RequestTimedOut,
+			Types: []string{"Temporary", "Timeout"},
+			Tags:  []errors.Tag{{Name: "kafka_code", Value: "7"}},
+		},
+
+		errorstest.AdapterTest{
+			Error: kafka.NotEnoughReplicas,
+			Types: []string{"Temporary"},
+			Tags:  []errors.Tag{{Name: "kafka_code", Value: "19"}},
+		},
+	)
+}
+
+func TestCode(t *testing.T) {
+	err, _ := Adapt(kafka.OffsetOutOfRange)
+
+	if code := err.(interface{ Code() int }).Code(); code != 1 {
+		t.Error("bad kafka error code:", code)
+	}
+}
+
+func TestAdaptWithNetErrors(t *testing.T) {
+	// kafka.Error implements net.Error, the fallback adapter of the neterrors
+	// package must not take over the errors recognized by this package.
+	err := errors.Adapt(kafka.LeaderNotAvailable)
+
+	if _, ok := err.(*kafkaError); !ok {
+		t.Fatalf("the error was not adapted by the kafkaerrors package: %T", err)
+	}
+
+	if !errors.Is("LeaderNotAvailable", err) {
+		t.Error("bad error types:", errors.Types(err))
+	}
+
+	if tags := errors.Tags(err); len(tags) != 1 || tags[0] != errors.TInt("kafka_code", int64(kafka.LeaderNotAvailable)) {
+		t.Error("bad error tags:", tags)
+	}
+}
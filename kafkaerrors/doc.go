@@ -0,0 +1,7 @@
+// Package kafkaerrors provides functions to adapt errors of the
+// github.com/segmentio/kafka-go package into errors compatible with the
+// errors-go package.
+//
+// Importing this package installs the kafka errors adapters on the global set
+// of adapters of the parent errors-go package.
+package kafkaerrors
@@ -0,0 +1,108 @@
+package errors
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Metadata carries debugging information captured when errors are created by
+// functions like New or Errorf, after enabling the feature with
+// SetCaptureMetadata. It is unrelated to the Metadata field of Value, which is
+// configured with SetValueMetadata.
+type Metadata struct {
+	// The time at which the error was created, according to Now.
+	Time time.Time
+
+	// The id of the goroutine that created the error.
+	Goroutine uint64
+}
+
+// String returns a human-readable representation of m.
+func (m Metadata) String() string {
+	return "goroutine " + strconv.FormatUint(m.Goroutine, 10) + " at " + m.Time.Format(time.RFC3339Nano)
+}
+
+// captureMetadataEnabled is set to 1 when errors must capture metadata.
+var captureMetadataEnabled int32
+
+// SetCaptureMetadata enables or disables the capture of the creation time and
+// goroutine id of errors created by functions like New or Errorf. The metadata
+// is exposed by a Metadata() Metadata method of the errors, and included in the
+// output of formatting errors with "%+v":
+//
+//	if e, ok := err.(interface{ Metadata() errors.Metadata }); ok {
+//		m := e.Metadata()
+//		// ...
+//	}
+//
+// The feature is intended to help debugging concurrency issues, it is disabled
+// by default because capturing the goroutine id is expensive and the output is
+// not stable across runs.
+func SetCaptureMetadata(enable bool) {
+	var v int32
+	if enable {
+		v = 1
+	}
+	atomic.StoreInt32(&captureMetadataEnabled, v)
+}
+
+// captureMetadata returns the metadata of an error created by the calling
+// goroutine, or nil if the capture is disabled.
+func captureMetadata() *Metadata {
+	if atomic.LoadInt32(&captureMetadataEnabled) == 0 {
+		return nil
+	}
+	return &Metadata{
+		Time:      Now(),
+		Goroutine: goroutineID(),
+	}
+}
+
+// goroutineID returns the id of the calling goroutine, parsed from the header
+// of its stack trace, which has the form "goroutine 42 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+type errorMetadata interface {
+	Metadata() Metadata
+}
+
+// inspectMetadata returns the metadata of the first error on the path of causes
+// of err that Inspect follows which carries metadata.
+func inspectMetadata(err error) (Metadata, bool) {
+	var path errorPath
+
+	for err != nil && !path.contains(err) {
+		path = append(path, err)
+
+		if e, ok := err.(errorMetadata); ok {
+			if m := e.Metadata(); !m.Time.IsZero() {
+				return m, true
+			}
+		}
+
+		switch e := err.(type) {
+		case errorCauses:
+			return Metadata{}, false
+
+		case errorCause:
+			err = e.Cause()
+
+		default:
+			return Metadata{}, false
+		}
+	}
+
+	return Metadata{}, false
+}
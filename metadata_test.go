@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCaptureMetadata(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	SetClock(func() time.Time { return now })
+	defer SetClock(nil)
+
+	t.Run("disabled", func(t *testing.T) {
+		err := New("oops")
+
+		if m := err.(errorMetadata).Metadata(); m != (Metadata{}) {
+			t.Error("unexpected metadata on error:", m)
+		}
+
+		if s := fmt.Sprintf("%+v", err); strings.Contains(s, "goroutine") {
+			t.Errorf("unexpected metadata in %%+v output: %q", s)
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		SetCaptureMetadata(true)
+		defer SetCaptureMetadata(false)
+
+		err := Wrap(New("oops"), "context")
+
+		m, ok := inspectMetadata(err)
+		if !ok {
+			t.Fatal("missing metadata on error")
+		}
+
+		if !m.Time.Equal(now) {
+			t.Error("bad creation time:", m.Time)
+		}
+
+		if m.Goroutine == 0 {
+			t.Error("missing goroutine id")
+		}
+
+		if s := fmt.Sprintf("%+v", err); !strings.Contains(s, m.String()) {
+			t.Errorf("missing metadata in %%+v output: %q", s)
+		}
+
+		if s := fmt.Sprintf("%v", err); strings.Contains(s, "goroutine") {
+			t.Errorf("unexpected metadata in %%v output: %q", s)
+		}
+	})
+}
+
+func TestGoroutineID(t *testing.T) {
+	id := goroutineID()
+	ch := make(chan uint64)
+
+	go func() { ch <- goroutineID() }()
+
+	if other := <-ch; other == id || other == 0 || id == 0 {
+		t.Error("bad goroutine ids:", id, other)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build go1.18
+// +build go1.18
+
+package errors
+
+// Must returns v if err is nil, otherwise it panics with err wrapped with a
+// capture of the stack trace, which makes it available to programs recovering
+// from the panic.
+//
+//	n := errors.Must(strconv.Atoi(s))
+//
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(WithStackTrace(err, CaptureStackTrace(1)))
+	}
+	return v
+}
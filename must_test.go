@@ -0,0 +1,43 @@
+//go:build go1.18
+// +build go1.18
+
+package errors
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMust(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		if v := Must(42, nil); v != 42 {
+			t.Error("bad value:", v)
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		cause := errors.New("hello")
+
+		defer func() {
+			err, ok := recover().(error)
+
+			if !ok {
+				t.Fatal("the panic value must be an error")
+			}
+
+			if Cause(err) != cause {
+				t.Error("bad cause of the panic value:", Cause(err))
+			}
+
+			if stack := stackTrace(err); len(stack) == 0 {
+				t.Error("the panic value has no stack trace")
+			} else if name := stack[0].name(); !strings.Contains(name, "TestMust") {
+				t.Error("bad first frame of the stack trace:", name)
+			}
+		}()
+
+		Must("", cause)
+		t.Error("Must did not panic")
+	})
+}
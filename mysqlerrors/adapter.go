@@ -0,0 +1,70 @@
+package mysqlerrors
+
+import (
+	"github.com/go-sql-driver/mysql"
+	errors "github.com/segmentio/errors-go"
+)
+
+// Adapt checks the type of err is a server error returned by the mysql driver,
+// and adapts it to make error types discoverable using the errors.Is function.
+// The error types are derived from the error number.
+//
+// This function is automatically installed as a global adapter when importing
+// the mysqlerrors package, a program likely should use errors.Adapt instead of
+// calling this adapter directly.
+func Adapt(err error) (error, bool) {
+	if e, ok := err.(*mysql.MySQLError); ok {
+		return &mysqlError{cause: e}, true
+	}
+	return err, false
+}
+
+// Error numbers recognized by the adapter, see
+// https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+const (
+	duplicateEntry  = 1062
+	lockWaitTimeout = 1205
+	lockDeadlock    = 1213
+)
+
+type mysqlError struct {
+	cause *mysql.MySQLError
+}
+
+func (e *mysqlError) Cause() error { return e.cause }
+
+func (e *mysqlError) Error() string { return e.cause.Error() }
+
+// Number returns the error number of the mysql error.
+func (e *mysqlError) Number() uint16 { return e.cause.Number }
+
+func (e *mysqlError) Tags() []errors.Tag {
+	tags := []errors.Tag{errors.TInt("mysql_errno", int64(e.cause.Number))}
+
+	if e.cause.SQLState != [5]byte{} {
+		tags = append(tags, errors.T("sqlstate", string(e.cause.SQLState[:])))
+	}
+
+	return tags
+}
+
+// MySQL-specific error types
+
+func (e *mysqlError) DuplicateEntry() bool { return e.is(duplicateEntry) }
+
+func (e *mysqlError) LockWaitTimeout() bool { return e.is(lockWaitTimeout) }
+
+func (e *mysqlError) LockDeadlock() bool { return e.is(lockDeadlock) }
+
+func (e *mysqlError) is(number uint16) bool { return e.cause.Number == number }
+
+// Common error types
+
+func (e *mysqlError) AlreadyExists() bool { return e.DuplicateEntry() }
+
+func (e *mysqlError) Conflict() bool { return e.DuplicateEntry() }
+
+func (e *mysqlError) Temporary() bool {
+	return e.LockDeadlock() ||
+		e.LockWaitTimeout()
+}
@@ -0,0 +1,48 @@
+package mysqlerrors
+
+import (
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	errors "github.com/segmentio/errors-go"
+	"github.com/segmentio/errors-go/errorstest"
+)
+
+func TestAdapt(t *testing.T) {
+	errorstest.TestAdapter(t, errors.AdapterFunc(Adapt),
+		errorstest.AdapterTest{
+			Error: &mysql.MySQLError{Number: 1064, Message: "syntax error"},
+			Types: []string{},
+			Tags:  []errors.Tag{{Name: "mysql_errno", Value: "1064"}},
+		},
+
+		errorstest.AdapterTest{
+			Error: &mysql.MySQLError{Number: 1062, SQLState: [5]byte{'2', '3', '0', '0', '0'}, Message: "Duplicate entry"},
+			Types: []string{"AlreadyExists", "Conflict", "DuplicateEntry"},
+			Tags: []errors.Tag{
+				{Name: "mysql_errno", Value: "1062"},
+				{Name: "sqlstate", Value: "23000"},
+			},
+		},
+
+		errorstest.AdapterTest{
+			Error: &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"},
+			Types: []string{"LockWaitTimeout", "Temporary"},
+			Tags:  []errors.Tag{{Name: "mysql_errno", Value: "1205"}},
+		},
+
+		errorstest.AdapterTest{
+			Error: &mysql.MySQLError{Number: 1213, Message: "Deadlock found"},
+			Types: []string{"LockDeadlock", "Temporary"},
+			Tags:  []errors.Tag{{Name: "mysql_errno", Value: "1213"}},
+		},
+	)
+}
+
+func TestNumber(t *testing.T) {
+	err, _ := Adapt(&mysql.MySQLError{Number: 1062})
+
+	if number := err.(interface{ Number() uint16 }).Number(); number != 1062 {
+		t.Error("bad error number:", number)
+	}
+}
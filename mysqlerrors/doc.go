@@ -0,0 +1,7 @@
+// Package mysqlerrors provides functions to adapt errors of the
+// github.com/go-sql-driver/mysql package into errors compatible with the
+// errors-go package.
+//
+// Importing this package installs the mysql errors adapters on the global set
+// of adapters of the parent errors-go package.
+package mysqlerrors
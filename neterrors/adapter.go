@@ -2,18 +2,30 @@ package neterrors
 
 import (
 	"net"
+	"os"
 	"strings"
+	"syscall"
 )
 
 // Adapt checks the type of err and if it matches one of the error types or one
 // of the error values of the standard net package, adapts it to make error
 // types discoverable using the errors.Is function.
 //
+// Other errors implementing the net.Error interface are adapted to expose their
+// Timeout and Temporary types by a fallback adapter, which is only tried on the
+// errors that no other adapters recognized, and skips errors that were already
+// adapted, which is detected by the presence of a Cause method. This way, errors
+// that also have a dedicated adapter, like the kafka.Error type, keep the types
+// and tags of their own adapter.
+//
 // This function is automatically installed as a global adapter when importing
 // the neterrors package, a program likely should use errors.Adapt instead of
 // calling this adapter directly.
 func Adapt(err error) (error, bool) {
 	switch e := err.(type) {
+	case *addrError, *dnsError, *parseError, *opError, *netError, *validation:
+		return err, false
+
 	case *net.AddrError:
 		return &addrError{e}, true
 
@@ -35,6 +47,22 @@ func Adapt(err error) (error, bool) {
 	return err, false
 }
 
+// adaptNetError is the fallback adapter of errors implementing net.Error.
+func adaptNetError(err error) (error, bool) {
+	if e, ok := err.(net.Error); ok && !isAdapted(e) {
+		return &netError{e}, true
+	}
+
+	return err, false
+}
+
+// isAdapted returns true if err is the output of an error adapter, which all
+// expose the error that they adapted with a Cause method.
+func isAdapted(err error) bool {
+	_, ok := err.(interface{ Cause() error })
+	return ok
+}
+
 type addrError struct{ cause error }
 
 func (e *addrError) Cause() error  { return e.cause }
@@ -77,6 +105,25 @@ func (e *opError) Temporary() bool   { return e.cause.Temporary() }
 func (e *opError) Timeout() bool     { return e.cause.Timeout() }
 func (e *opError) Unreachable() bool { return e.cause.Op == "dial" || e.cause.Op == "write" }
 
+func (e *opError) ConnectionRefused() bool { return e.errno() == syscall.ECONNREFUSED }
+func (e *opError) ConnectionReset() bool   { return e.errno() == syscall.ECONNRESET }
+
+func (e *opError) errno() syscall.Errno {
+	err := e.cause.Err
+	if s, ok := err.(*os.SyscallError); ok {
+		err = s.Err
+	}
+	errno, _ := err.(syscall.Errno)
+	return errno
+}
+
+type netError struct{ cause net.Error }
+
+func (e *netError) Cause() error    { return e.cause }
+func (e *netError) Error() string   { return e.cause.Error() }
+func (e *netError) Temporary() bool { return e.cause.Temporary() }
+func (e *netError) Timeout() bool   { return e.cause.Timeout() }
+
 type validation struct{ cause error }
 
 func (e *validation) Cause() error     { return e.cause }
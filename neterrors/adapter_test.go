@@ -3,14 +3,25 @@ package neterrors
 import (
 	"io"
 	"net"
+	"os"
+	"syscall"
 	"testing"
 
 	errors "github.com/segmentio/errors-go"
 	"github.com/segmentio/errors-go/errorstest"
 )
 
+// adaptAll applies the adapter and the fallback adapter installed by the
+// package, in the order used by errors.Adapt.
+func adaptAll(err error) (error, bool) {
+	if e, ok := Adapt(err); ok {
+		return e, true
+	}
+	return adaptNetError(err)
+}
+
 func TestAdapt(t *testing.T) {
-	errorstest.TestAdapter(t, errors.AdapterFunc(Adapt),
+	errorstest.TestAdapter(t, errors.AdapterFunc(adaptAll),
 		errorstest.AdapterTest{
 			Error: &net.AddrError{Err: "whatever"},
 			Types: []string{},
@@ -86,10 +97,40 @@ func TestAdapt(t *testing.T) {
 			Types: []string{"Temporary", "Timeout"},
 		},
 
+		errorstest.AdapterTest{
+			Error: &net.OpError{Err: os.NewSyscallError("connect", syscall.ECONNREFUSED), Op: "dial"},
+			Types: []string{"ConnectionRefused", "Unreachable"},
+		},
+
+		errorstest.AdapterTest{
+			Error: &net.OpError{Err: os.NewSyscallError("read", syscall.ECONNRESET), Op: "read"},
+			Types: []string{"ConnectionReset"},
+		},
+
+		errorstest.AdapterTest{
+			Error: &net.OpError{Err: syscall.ECONNRESET, Op: "write"},
+			Types: []string{"ConnectionReset", "Unreachable"},
+		},
+
 		errorstest.AdapterTest{
 			Error: net.ErrWriteToConnected,
 			Types: []string{"Validation"},
 		},
+
+		errorstest.AdapterTest{
+			Error: &timeout{},
+			Types: []string{"Temporary", "Timeout"},
+		},
+
+		errorstest.AdapterTest{
+			Error: &customNetError{temporary: true},
+			Types: []string{"Temporary"},
+		},
+
+		errorstest.AdapterTest{
+			Error: &customNetError{},
+			Types: []string{},
+		},
 	)
 }
 
@@ -98,3 +139,82 @@ type timeout struct{}
 func (*timeout) Error() string   { return "timeout" }
 func (*timeout) Timeout() bool   { return true }
 func (*timeout) Temporary() bool { return true }
+
+type customNetError struct{ temporary bool }
+
+func (*customNetError) Error() string     { return "net error" }
+func (*customNetError) Timeout() bool     { return false }
+func (e *customNetError) Temporary() bool { return e.temporary }
+
+func TestAdaptAdapted(t *testing.T) {
+	for _, err := range []error{
+		&net.OpError{Err: &timeout{}, Op: "read"},
+		&customNetError{},
+	} {
+		adapted, ok := adaptAll(err)
+		if !ok {
+			t.Fatalf("%T was not adapted", err)
+		}
+		if e, ok := adaptAll(adapted); ok || e != adapted {
+			t.Errorf("%T was adapted again to %T", adapted, e)
+		}
+	}
+
+	wrapped := &adaptedNetError{cause: &customNetError{}}
+	if e, ok := adaptAll(wrapped); ok || e != wrapped {
+		t.Errorf("error adapted by another adapter was adapted again to %T", e)
+	}
+}
+
+// adaptedNetError mimics the output of adapters of other packages, for example
+// HTTP or gRPC errors which also implement net.Error.
+type adaptedNetError struct{ cause error }
+
+func (e *adaptedNetError) Cause() error    { return e.cause }
+func (e *adaptedNetError) Error() string   { return e.cause.Error() }
+func (e *adaptedNetError) Timeout() bool   { return false }
+func (e *adaptedNetError) Temporary() bool { return true }
+
+func TestAdaptDedicatedAdapter(t *testing.T) {
+	// The package registered its adapters when it was initialized, before the
+	// dedicated adapter below, which must still take precedence.
+	errors.Register(errors.AdapterFunc(func(err error) (error, bool) {
+		if e, ok := err.(*codeError); ok {
+			return &adaptedCodeError{cause: e}, true
+		}
+		return err, false
+	}))
+
+	err := errors.Adapt(&codeError{})
+
+	if _, ok := err.(*adaptedCodeError); !ok {
+		t.Fatalf("the error was not adapted by its dedicated adapter: %T", err)
+	}
+
+	if !errors.Is("Conflict", err) {
+		t.Error("bad error types:", errors.Types(err))
+	}
+
+	if tags := errors.Tags(err); len(tags) != 1 || tags[0] != errors.T("code", "42") {
+		t.Error("bad error tags:", tags)
+	}
+
+	if !errors.Is("Temporary", errors.Adapt(&customNetError{temporary: true})) {
+		t.Error("errors implementing net.Error are not adapted by the fallback")
+	}
+}
+
+// codeError mimics errors of other packages which implement net.Error and have
+// a dedicated adapter, like kafka.Error.
+type codeError struct{}
+
+func (*codeError) Error() string   { return "code 42" }
+func (*codeError) Timeout() bool   { return false }
+func (*codeError) Temporary() bool { return true }
+
+type adaptedCodeError struct{ cause error }
+
+func (e *adaptedCodeError) Cause() error       { return e.cause }
+func (e *adaptedCodeError) Error() string      { return e.cause.Error() }
+func (e *adaptedCodeError) Conflict() bool     { return true }
+func (e *adaptedCodeError) Tags() []errors.Tag { return []errors.Tag{errors.T("code", "42")} }
@@ -4,4 +4,5 @@ import errors "github.com/segmentio/errors-go"
 
 func init() {
 	errors.Register(errors.AdapterFunc(Adapt))
+	errors.RegisterFallback(errors.AdapterFunc(adaptNetError))
 }
@@ -0,0 +1,95 @@
+package pgxerrors
+
+import (
+	"github.com/jackc/pgx/v5/pgconn"
+	errors "github.com/segmentio/errors-go"
+)
+
+// Adapt checks the type of err is a postgres error returned by pgx, and adapts
+// it to make error types discoverable using the errors.Is function. The error
+// types are derived from the SQLSTATE code of the error.
+//
+// This function is automatically installed as a global adapter when importing
+// the pgxerrors package, a program likely should use errors.Adapt instead of
+// calling this adapter directly.
+func Adapt(err error) (error, bool) {
+	if e, ok := err.(*pgconn.PgError); ok {
+		return &pgError{cause: e}, true
+	}
+	return err, false
+}
+
+// SQLSTATE codes recognized by the adapter, see
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	notNullViolation     = "23502"
+	foreignKeyViolation  = "23503"
+	uniqueViolation      = "23505"
+	checkViolation       = "23514"
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+	tooManyConnections   = "53300"
+)
+
+type pgError struct {
+	cause *pgconn.PgError
+}
+
+func (e *pgError) Cause() error { return e.cause }
+
+func (e *pgError) Error() string { return e.cause.Error() }
+
+// Code returns the SQLSTATE code of the postgres error.
+func (e *pgError) Code() string { return e.cause.Code }
+
+func (e *pgError) Tags() []errors.Tag {
+	tags := []errors.Tag{errors.T("sqlstate", e.cause.Code)}
+
+	if e.cause.ConstraintName != "" {
+		tags = append(tags, errors.T("constraint", e.cause.ConstraintName))
+	}
+
+	if e.cause.TableName != "" {
+		tags = append(tags, errors.T("table", e.cause.TableName))
+	}
+
+	return tags
+}
+
+// Postgres-specific error types
+
+func (e *pgError) NotNullViolation() bool { return e.is(notNullViolation) }
+
+func (e *pgError) ForeignKeyViolation() bool { return e.is(foreignKeyViolation) }
+
+func (e *pgError) UniqueViolation() bool { return e.is(uniqueViolation) }
+
+func (e *pgError) CheckViolation() bool { return e.is(checkViolation) }
+
+func (e *pgError) SerializationFailure() bool { return e.is(serializationFailure) }
+
+func (e *pgError) DeadlockDetected() bool { return e.is(deadlockDetected) }
+
+func (e *pgError) TooManyConnections() bool { return e.is(tooManyConnections) }
+
+func (e *pgError) is(code string) bool { return e.cause.Code == code }
+
+// Common error types
+
+func (e *pgError) AlreadyExists() bool { return e.UniqueViolation() }
+
+func (e *pgError) Conflict() bool { return e.UniqueViolation() }
+
+func (e *pgError) Throttled() bool { return e.TooManyConnections() }
+
+func (e *pgError) Validation() bool {
+	return e.NotNullViolation() ||
+		e.ForeignKeyViolation() ||
+		e.CheckViolation()
+}
+
+func (e *pgError) Temporary() bool {
+	return e.SerializationFailure() ||
+		e.DeadlockDetected() ||
+		e.TooManyConnections()
+}
@@ -0,0 +1,7 @@
+// Package pgxerrors provides functions to adapt errors of the
+// github.com/jackc/pgx/v5/pgconn package into errors compatible with the
+// errors-go package.
+//
+// Importing this package installs the pgx errors adapters on the global set of
+// adapters of the parent errors-go package.
+package pgxerrors
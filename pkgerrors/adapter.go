@@ -1,40 +1,87 @@
 package pkgerrors
 
 import (
+	"reflect"
+	"strings"
+
 	pkgerrors "github.com/pkg/errors"
 	"github.com/segmentio/errors-go"
 )
 
 // Adapt adapts err if it was generated by the github.com/pkg/errors package.
 //
-// Note that most error types for github.com/pkg/errors are compatible with the
-// design of the errors-go package without needing to be modified. The only case
-// where the error is actually changed is for errors with stack traces, because
-// the StackTrace types are concrete types exposed by each package, and even if
-// they are very similar they cannot be implicitly converted by the Go compiler.
+// Errors with stack traces need to be adapted because the StackTrace types are
+// concrete types exposed by each package, and even if they are very similar
+// they cannot be implicitly converted by the Go compiler. Errors of the package
+// that have a cause but no stack trace, like the ones returned by
+// pkgerrors.WithMessage, are adapted as well so their messages are exposed.
+//
+// The chain of causes of adapted errors is reconstructed when the error is
+// adapted, with one errors-go node per layer, so the messages added by
+// functions like pkgerrors.Wrap and the stack traces of each layer are all
+// exposed. Errors created by pkgerrors.New remain at the end of the chain, so
+// errors.Cause returns them unchanged.
 func Adapt(err error) (error, bool) {
 	switch err.(type) {
 	case errorWithStack:
-		return &adapter{cause: err}, true
-	default:
-		return err, false
+		return adaptLayer(err), true
+	case errorCause:
+		if isPkgError(err) {
+			return adaptLayer(err), true
+		}
+	}
+	return err, false
+}
+
+// adaptLayer adapts a single layer of a chain of errors produced by the
+// github.com/pkg/errors package, and the layers below it. Errors that were not
+// produced by the package are passed to the global adapters.
+func adaptLayer(err error) error {
+	switch e := err.(type) {
+	case errorWithStack:
+		a := &adapter{err: err, cause: err}
+		if c, ok := err.(errorCause); ok {
+			a.cause = adaptCause(c)
+		}
+		return a
+
+	case errorCause:
+		if isPkgError(err) {
+			return &messageAdapter{err: err, cause: adaptCause(e)}
+		}
 	}
+	return errors.Adapt(err)
 }
 
+func adaptCause(err errorCause) error {
+	if cause := err.Cause(); cause != nil {
+		return adaptLayer(cause)
+	}
+	return nil
+}
+
+// adapter adapts errors with stack traces. Errors created by pkgerrors.New
+// have no cause, they are exposed as the cause of the adapter so their identity
+// is preserved.
 type adapter struct {
+	err   error
 	cause error
 }
 
 func (a *adapter) Error() string {
-	return a.cause.Error()
+	return a.err.Error()
 }
 
-func (e *adapter) Cause() error {
-	return e.cause
+func (a *adapter) Message() string {
+	return "" // the message is carried by the cause
+}
+
+func (a *adapter) Cause() error {
+	return a.cause
 }
 
 func (a *adapter) StackTrace() errors.StackTrace {
-	e := a.cause.(errorWithStack)
+	e := a.err.(errorWithStack)
 	stack1 := e.StackTrace()
 	stack2 := make(errors.StackTrace, len(stack1))
 
@@ -45,6 +92,44 @@ func (a *adapter) StackTrace() errors.StackTrace {
 	return stack2
 }
 
+// messageAdapter adapts errors created by pkgerrors.WithMessage, which prefix
+// the message of their cause.
+type messageAdapter struct {
+	err   error
+	cause error
+}
+
+func (a *messageAdapter) Error() string {
+	return a.err.Error()
+}
+
+func (a *messageAdapter) Message() string {
+	s := a.err.Error()
+	if cause := a.err.(errorCause).Cause(); cause != nil {
+		s = strings.TrimSuffix(s, ": "+cause.Error())
+	}
+	return s
+}
+
+func (a *messageAdapter) Cause() error {
+	return a.cause
+}
+
+func isPkgError(err error) bool {
+	t := reflect.TypeOf(err)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	p := t.PkgPath()
+	return p == pkgPath || strings.HasSuffix(p, "/vendor/"+pkgPath)
+}
+
+const pkgPath = "github.com/pkg/errors"
+
 type errorWithStack interface {
 	StackTrace() pkgerrors.StackTrace
 }
+
+type errorCause interface {
+	Cause() error
+}
@@ -0,0 +1,104 @@
+package pkgerrors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/segmentio/errors-go"
+)
+
+func TestAdaptWrapChain(t *testing.T) {
+	e0 := stderrors.New("base error")
+	e1 := pkgerrors.Wrap(pkgerrors.WithStack(e0), "msg")
+	e2, ok := Adapt(e1)
+
+	if !ok {
+		t.Fatal("errors from the github.com/pkg/errors package that have a stack must be adapted")
+	}
+
+	if s1, s2 := e1.Error(), e2.Error(); s1 != s2 {
+		t.Errorf("bad error string: %q != %q", s2, s1)
+	}
+
+	msgs, _, _, stacks, _ := errors.Inspect(e2)
+
+	if len(msgs) != 2 || msgs[0] != "msg" || msgs[1] != "base error" {
+		t.Error("bad messages:", msgs)
+	}
+
+	if len(stacks) != 2 {
+		t.Error("bad number of stack traces:", len(stacks))
+	}
+
+	if cause := errors.Cause(e2); cause != e0 {
+		t.Error("bad error cause:", cause)
+	}
+
+	s := fmt.Sprintf("%+v", errors.WithMessage(e2, "outer"))
+
+	if !strings.Contains(s, "outer: msg: base error") {
+		t.Error("the messages are missing from the formatted error:", s)
+	}
+
+	if n := strings.Count(s, "TestAdaptWrapChain"); n != 2 {
+		t.Errorf("the stack traces are missing from the formatted error (%d):\n%s", n, s)
+	}
+}
+
+func TestAdaptWithMessage(t *testing.T) {
+	e0 := stderrors.New("base error")
+	e1 := pkgerrors.WithMessage(e0, "msg")
+	e2, ok := Adapt(e1)
+
+	if !ok {
+		t.Fatal("errors from the github.com/pkg/errors package that have a cause must be adapted")
+	}
+
+	if s1, s2 := e1.Error(), e2.Error(); s1 != s2 {
+		t.Errorf("bad error string: %q != %q", s2, s1)
+	}
+
+	if msgs, _, _, _, _ := errors.Inspect(e2); len(msgs) != 2 || msgs[0] != "msg" || msgs[1] != "base error" {
+		t.Error("bad messages:", msgs)
+	}
+
+	if cause := errors.Cause(e2); cause != e0 {
+		t.Error("bad error cause:", cause)
+	}
+
+	if _, ok := Adapt(&foreignCause{e0}); ok {
+		t.Error("errors with a cause that are not from the github.com/pkg/errors package must not be adapted")
+	}
+}
+
+type foreignCause struct{ cause error }
+
+func (e *foreignCause) Error() string { return e.cause.Error() }
+func (e *foreignCause) Cause() error  { return e.cause }
+
+var errSentinel = pkgerrors.New("sentinel")
+
+func TestAdaptSentinel(t *testing.T) {
+	err := errors.Wrap(errSentinel, "context")
+
+	if cause := errors.Cause(err); cause != errSentinel {
+		t.Error("bad error cause:", cause)
+	}
+
+	if s := err.Error(); s != "context: sentinel" {
+		t.Errorf("bad error string: %q", s)
+	}
+
+	if msgs, _, _, stacks, _ := errors.Inspect(err); len(msgs) != 2 || msgs[0] != "context" || msgs[1] != "sentinel" || len(stacks) != 2 {
+		t.Error("bad messages or stack traces:", msgs, len(stacks))
+	}
+
+	adapted, _ := Adapt(pkgerrors.Wrap(errSentinel, "context"))
+
+	if c1, c2 := adapted.(errorCause).Cause(), adapted.(errorCause).Cause(); c1 != c2 {
+		t.Error("the chain of adapted causes must be constructed once:", c1, c2)
+	}
+}
@@ -0,0 +1,95 @@
+package pqerrors
+
+import (
+	"github.com/lib/pq"
+	errors "github.com/segmentio/errors-go"
+)
+
+// Adapt checks the type of err is a postgres error returned by pq, and adapts
+// it to make error types discoverable using the errors.Is function. The error
+// types are derived from the SQLSTATE code of the error.
+//
+// This function is automatically installed as a global adapter when importing
+// the pqerrors package, a program likely should use errors.Adapt instead of
+// calling this adapter directly.
+func Adapt(err error) (error, bool) {
+	if e, ok := err.(*pq.Error); ok {
+		return &pqError{cause: e}, true
+	}
+	return err, false
+}
+
+// SQLSTATE codes recognized by the adapter, see
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	notNullViolation     = "23502"
+	foreignKeyViolation  = "23503"
+	uniqueViolation      = "23505"
+	checkViolation       = "23514"
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+	tooManyConnections   = "53300"
+)
+
+type pqError struct {
+	cause *pq.Error
+}
+
+func (e *pqError) Cause() error { return e.cause }
+
+func (e *pqError) Error() string { return e.cause.Error() }
+
+// Code returns the SQLSTATE code of the postgres error.
+func (e *pqError) Code() string { return string(e.cause.Code) }
+
+func (e *pqError) Tags() []errors.Tag {
+	tags := []errors.Tag{errors.T("sqlstate", string(e.cause.Code))}
+
+	if e.cause.Constraint != "" {
+		tags = append(tags, errors.T("constraint", e.cause.Constraint))
+	}
+
+	if e.cause.Table != "" {
+		tags = append(tags, errors.T("table", e.cause.Table))
+	}
+
+	return tags
+}
+
+// Postgres-specific error types
+
+func (e *pqError) NotNullViolation() bool { return e.is(notNullViolation) }
+
+func (e *pqError) ForeignKeyViolation() bool { return e.is(foreignKeyViolation) }
+
+func (e *pqError) UniqueViolation() bool { return e.is(uniqueViolation) }
+
+func (e *pqError) CheckViolation() bool { return e.is(checkViolation) }
+
+func (e *pqError) SerializationFailure() bool { return e.is(serializationFailure) }
+
+func (e *pqError) DeadlockDetected() bool { return e.is(deadlockDetected) }
+
+func (e *pqError) TooManyConnections() bool { return e.is(tooManyConnections) }
+
+func (e *pqError) is(code string) bool { return string(e.cause.Code) == code }
+
+// Common error types
+
+func (e *pqError) AlreadyExists() bool { return e.UniqueViolation() }
+
+func (e *pqError) Conflict() bool { return e.UniqueViolation() }
+
+func (e *pqError) Throttled() bool { return e.TooManyConnections() }
+
+func (e *pqError) Validation() bool {
+	return e.NotNullViolation() ||
+		e.ForeignKeyViolation() ||
+		e.CheckViolation()
+}
+
+func (e *pqError) Temporary() bool {
+	return e.SerializationFailure() ||
+		e.DeadlockDetected() ||
+		e.TooManyConnections()
+}
@@ -0,0 +1,79 @@
+package pqerrors
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	errors "github.com/segmentio/errors-go"
+	"github.com/segmentio/errors-go/errorstest"
+)
+
+func TestAdapt(t *testing.T) {
+	errorstest.TestAdapter(t, errors.AdapterFunc(Adapt),
+		errorstest.AdapterTest{
+			Error: &pq.Error{Code: "42601"},
+			Types: []string{},
+			Tags:  []errors.Tag{{Name: "sqlstate", Value: "42601"}},
+		},
+
+		errorstest.AdapterTest{
+			Error: &pq.Error{Code: "23502", Table: "users"},
+			Types: []string{"NotNullViolation", "Validation"},
+			Tags: []errors.Tag{
+				{Name: "sqlstate", Value: "23502"},
+				{Name: "table", Value: "users"},
+			},
+		},
+
+		errorstest.AdapterTest{
+			Error: &pq.Error{Code: "23503", Constraint: "users_org_id_fkey"},
+			Types: []string{"ForeignKeyViolation", "Validation"},
+			Tags: []errors.Tag{
+				{Name: "constraint", Value: "users_org_id_fkey"},
+				{Name: "sqlstate", Value: "23503"},
+			},
+		},
+
+		errorstest.AdapterTest{
+			Error: &pq.Error{Code: "23505", Constraint: "users_pkey", Table: "users"},
+			Types: []string{"AlreadyExists", "Conflict", "UniqueViolation"},
+			Tags: []errors.Tag{
+				{Name: "constraint", Value: "users_pkey"},
+				{Name: "sqlstate", Value: "23505"},
+				{Name: "table", Value: "users"},
+			},
+		},
+
+		errorstest.AdapterTest{
+			Error: &pq.Error{Code: "23514"},
+			Types: []string{"CheckViolation", "Validation"},
+			Tags:  []errors.Tag{{Name: "sqlstate", Value: "23514"}},
+		},
+
+		errorstest.AdapterTest{
+			Error: &pq.Error{Code: "40001"},
+			Types: []string{"SerializationFailure", "Temporary"},
+			Tags:  []errors.Tag{{Name: "sqlstate", Value: "40001"}},
+		},
+
+		errorstest.AdapterTest{
+			Error: &pq.Error{Code: "40P01"},
+			Types: []string{"DeadlockDetected", "Temporary"},
+			Tags:  []errors.Tag{{Name: "sqlstate", Value: "40P01"}},
+		},
+
+		errorstest.AdapterTest{
+			Error: &pq.Error{Code: "53300"},
+			Types: []string{"Temporary", "Throttled", "TooManyConnections"},
+			Tags:  []errors.Tag{{Name: "sqlstate", Value: "53300"}},
+		},
+	)
+}
+
+func TestCode(t *testing.T) {
+	err, _ := Adapt(&pq.Error{Code: "23505"})
+
+	if code := err.(interface{ Code() string }).Code(); code != "23505" {
+		t.Error("bad SQLSTATE code:", code)
+	}
+}
@@ -0,0 +1,7 @@
+// Package pqerrors provides functions to adapt errors of the
+// github.com/lib/pq package into errors compatible with the
+// errors-go package.
+//
+// Importing this package installs the pq errors adapters on the global set of
+// adapters of the parent errors-go package.
+package pqerrors
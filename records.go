@@ -0,0 +1,74 @@
+package errors
+
+import "strings"
+
+// Record is a flat representation of a node in the graph of causes of an
+// error, as returned by Records.
+type Record struct {
+	// Types of the node, sorted and without duplicates, like the types returned
+	// by Inspect.
+	Types []string
+
+	// Message of the node, which combines the messages of the chain of
+	// wrappers that form the node, separated by ": ".
+	Message string
+
+	// Tags set on the node. When a tag name is set multiple times on the same
+	// node, the smallest value is retained, which is the first of the tags with
+	// this name in the sorted list returned by Inspect, regardless of which
+	// error of the node it was set on.
+	Tags map[string]string
+
+	// Depth of the node in the graph, the root has a depth of zero and the
+	// causes of a node at depth N are at depth N+1.
+	Depth int
+
+	// IsLeaf is true when the node has no causes.
+	IsLeaf bool
+}
+
+// Records flattens err into a list of records, one for each node of the graph
+// of its causes. Nodes are delimited the same way they are when errors are
+// formatted as trees: a chain of errors with a single cause forms one node, and
+// errors with multiple causes have one child node per cause.
+//
+// Records are emitted in depth-first order, each node appears before its causes
+// and causes appear in the order they were reported by the error:
+//
+//	err := errors.Wrap(errors.Join(A, B), "C")
+//
+//	// Message  Depth  IsLeaf
+//	// C        0      false
+//	// A        1      true
+//	// B        1      true
+//
+// If err is nil the function returns nil. Causes that form a cycle are not
+// visited again.
+func Records(err error) []Record {
+	if err == nil {
+		return nil
+	}
+	return appendRecords(nil, err, 0, nil)
+}
+
+func appendRecords(records []Record, err error, depth int, path errorPath) []Record {
+	msgs, types, tags, _, causes := Inspect(err)
+
+	records = append(records, Record{
+		Types:   types,
+		Message: strings.Join(msgs, ": "),
+		Tags:    makeTagsMap(tags...),
+		Depth:   depth,
+		IsLeaf:  len(causes) == 0,
+	})
+
+	path = append(path, err)
+
+	for _, cause := range causes {
+		if cause != nil && !path.contains(cause) {
+			records = appendRecords(records, cause, depth+1, path)
+		}
+	}
+
+	return records
+}
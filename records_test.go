@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecords(t *testing.T) {
+	err := WithTags(
+		Wrap(
+			Join(
+				WithTypes(New("A"), "NotFound"),
+				Join(New("B"), WithTags(New("C"), T("x", "1"))),
+			),
+			"D",
+		),
+		T("env", "test"),
+	)
+
+	expected := []Record{
+		{Message: "D", Tags: map[string]string{"env": "test"}, Depth: 0, IsLeaf: false},
+		{Message: "A", Types: []string{"NotFound"}, Depth: 1, IsLeaf: true},
+		{Depth: 1, IsLeaf: false},
+		{Message: "B", Depth: 2, IsLeaf: true},
+		{Message: "C", Tags: map[string]string{"x": "1"}, Depth: 2, IsLeaf: true},
+	}
+
+	if records := Records(err); !reflect.DeepEqual(records, expected) {
+		t.Error("bad records:")
+		t.Logf("expected: %+v", expected)
+		t.Logf("found:    %+v", records)
+	}
+
+	if records := Records(nil); records != nil {
+		t.Error("non-nil records returned for a nil error:", records)
+	}
+}
+
+func TestRecordsDuplicates(t *testing.T) {
+	err := WithTags(
+		WithTypes(
+			WithTags(WithTypes(New("A"), "Timeout", "NotFound"), T("key", "inner")),
+			"NotFound",
+		),
+		T("key", "outer"),
+	)
+
+	records := Records(err)
+
+	if len(records) != 1 {
+		t.Fatal("bad number of records:", len(records))
+	}
+
+	if types := records[0].Types; !reflect.DeepEqual(types, []string{"NotFound", "Timeout"}) {
+		t.Error("bad record types:", types)
+	}
+
+	if tags := records[0].Tags; !reflect.DeepEqual(tags, map[string]string{"key": "inner"}) {
+		t.Error("bad record tags:", tags)
+	}
+}
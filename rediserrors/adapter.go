@@ -0,0 +1,68 @@
+package rediserrors
+
+import (
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Adapt checks the type of err is a redis error, and adapts it to make error
+// types discoverable using the errors.Is function.
+//
+// The redis.Nil value returned on cache misses is adapted to an error of types
+// "CacheMiss" and "NotFound", and cluster replies asking the client to retry
+// are adapted to "Temporary" errors.
+//
+// This function is automatically installed as a global adapter when importing
+// the rediserrors package, a program likely should use errors.Adapt instead of
+// calling this adapter directly.
+func Adapt(err error) (error, bool) {
+	if err == redis.Nil {
+		return &cacheMiss{cause: err}, true
+	}
+	if e, ok := err.(redis.Error); ok {
+		return &replyError{cause: e}, true
+	}
+	return err, false
+}
+
+type cacheMiss struct {
+	cause error
+}
+
+func (e *cacheMiss) Cause() error { return e.cause }
+
+func (e *cacheMiss) Error() string { return e.cause.Error() }
+
+func (e *cacheMiss) CacheMiss() bool { return true }
+
+func (e *cacheMiss) NotFound() bool { return true }
+
+type replyError struct {
+	cause redis.Error
+}
+
+func (e *replyError) Cause() error { return e.cause }
+
+func (e *replyError) Error() string { return e.cause.Error() }
+
+// Redis-specific error types
+
+func (e *replyError) Moved() bool { return e.is("MOVED") }
+
+func (e *replyError) Ask() bool { return e.is("ASK") }
+
+func (e *replyError) TryAgain() bool { return e.is("TRYAGAIN") }
+
+func (e *replyError) ClusterDown() bool { return e.is("CLUSTERDOWN") }
+
+func (e *replyError) is(prefix string) bool {
+	s := e.cause.Error()
+	return strings.HasPrefix(s, prefix) && (len(s) == len(prefix) || s[len(prefix)] == ' ')
+}
+
+// Common error types
+
+func (e *replyError) Unavailable() bool { return e.TryAgain() || e.ClusterDown() }
+
+func (e *replyError) Temporary() bool { return e.Moved() || e.Ask() || e.Unavailable() }
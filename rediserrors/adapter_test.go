@@ -0,0 +1,51 @@
+package rediserrors
+
+import (
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	errors "github.com/segmentio/errors-go"
+	"github.com/segmentio/errors-go/errorstest"
+)
+
+func TestAdapt(t *testing.T) {
+	errorstest.TestAdapter(t, errors.AdapterFunc(Adapt),
+		errorstest.AdapterTest{
+			Error: redis.Nil,
+			Types: []string{"CacheMiss", "NotFound"},
+		},
+
+		errorstest.AdapterTest{
+			Error: reply("ERR wrong number of arguments for 'get' command"),
+			Types: []string{},
+		},
+
+		errorstest.AdapterTest{
+			Error: reply("MOVED 3999 127.0.0.1:6381"),
+			Types: []string{"Moved", "Temporary"},
+		},
+
+		errorstest.AdapterTest{
+			Error: reply("ASK 3999 127.0.0.1:6381"),
+			Types: []string{"Ask", "Temporary"},
+		},
+
+		errorstest.AdapterTest{
+			Error: reply("TRYAGAIN Multiple keys request during rehashing of slot"),
+			Types: []string{"Temporary", "TryAgain", "Unavailable"},
+		},
+
+		errorstest.AdapterTest{
+			Error: reply("CLUSTERDOWN The cluster is down"),
+			Types: []string{"ClusterDown", "Temporary", "Unavailable"},
+		},
+	)
+}
+
+// reply mimics the error type used by the redis client to report error replies
+// from the server.
+type reply string
+
+func (r reply) Error() string { return string(r) }
+
+func (reply) RedisError() {}
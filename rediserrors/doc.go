@@ -0,0 +1,7 @@
+// Package rediserrors provides functions to adapt errors of the
+// github.com/redis/go-redis/v9 package into errors compatible with the
+// errors-go package.
+//
+// Importing this package installs the redis errors adapters on the global set
+// of adapters of the parent errors-go package.
+package rediserrors
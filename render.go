@@ -0,0 +1,174 @@
+package errors
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+// RenderOptions configures the output of Render.
+type RenderOptions struct {
+	// When true, the error is rendered as the tree of its causes, with their
+	// types and tags, like formatting the error with "%v". Otherwise only the
+	// error message is rendered.
+	Verbose bool
+
+	// When true, ANSI colors are used around the types, tags, and source
+	// locations of stack frames in the verbose output. Unlike Fprint, Render
+	// does not know where the output is written, the program is expected to
+	// only set the option when it is a terminal.
+	Color bool
+
+	// The maximum number of characters on each line of the output, zero means
+	// unlimited. The error message is wrapped at word boundaries, lines of the
+	// verbose output which exceed the limit are truncated and end with "...".
+	MaxWidth int
+
+	// When true, the stack traces are included in the verbose output, like
+	// formatting the error with "%+v". The option has no effect when Verbose is
+	// false.
+	ShowStacks bool
+}
+
+// Render returns a representation of err intended to be shown to users of
+// command-line programs, for example:
+//
+//	fmt.Fprintln(os.Stderr, errors.Render(err, errors.RenderOptions{
+//		Verbose:  *verbose,
+//		Color:    isatty(os.Stderr),
+//		MaxWidth: 80,
+//	}))
+//
+// The returned string does not end with a newline. If err is nil, the function
+// returns an empty string.
+func Render(err error, opts RenderOptions) string {
+	if err == nil {
+		return ""
+	}
+
+	if !opts.Verbose {
+		return wrapLines(err.Error(), opts.MaxWidth)
+	}
+
+	s := &renderState{plus: opts.ShowStacks}
+	f := formatter{state: s, colors: opts.Color}
+	f.format(formatterContext{length: 1}, nil, err)
+
+	out := strings.TrimRight(s.String(), "\n")
+	if opts.MaxWidth > 0 {
+		lines := strings.Split(out, "\n")
+		for i, line := range lines {
+			lines[i] = truncateLine(line, opts.MaxWidth)
+		}
+		out = strings.Join(lines, "\n")
+	}
+	return out
+}
+
+// renderState implements fmt.State to let Render use the formatter.
+type renderState struct {
+	bytes.Buffer
+	plus bool
+}
+
+func (s *renderState) Width() (int, bool) { return 0, false }
+
+func (s *renderState) Precision() (int, bool) { return 0, false }
+
+func (s *renderState) Flag(c int) bool { return c == '+' && s.plus }
+
+// wrapLines wraps each line of s at word boundaries so they are at most width
+// characters long. Words longer than width are not split.
+func wrapLines(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	b := &bytes.Buffer{}
+
+	for i, line := range lines {
+		if i != 0 {
+			b.WriteByte('\n')
+		}
+
+		n := 0
+		for _, word := range strings.Fields(line) {
+			w := utf8.RuneCountInString(word)
+			switch {
+			case n == 0:
+			case n+1+w > width:
+				b.WriteByte('\n')
+				n = 0
+			default:
+				b.WriteByte(' ')
+				n++
+			}
+			b.WriteString(word)
+			n += w
+		}
+	}
+
+	return b.String()
+}
+
+// truncateLine truncates line to width visible characters, not counting ANSI
+// escape sequences, replacing the last ones with "..." when it was too long.
+func truncateLine(line string, width int) string {
+	const ellipsis = "..."
+
+	if visibleWidth(line) <= width {
+		return line
+	}
+
+	limit := width - len(ellipsis)
+	if limit < 0 {
+		limit = 0
+	}
+
+	b := &bytes.Buffer{}
+	n := 0
+
+	for i := 0; i < len(line); {
+		if j := escapeSequenceLength(line[i:]); j != 0 {
+			b.WriteString(line[i : i+j])
+			i += j
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(line[i:])
+		if n < limit {
+			b.WriteRune(r)
+			n++
+		}
+		i += size
+	}
+
+	b.WriteString(ellipsis)
+	return b.String()
+}
+
+func visibleWidth(s string) int {
+	n := 0
+	for i := 0; i < len(s); {
+		if j := escapeSequenceLength(s[i:]); j != 0 {
+			i += j
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		n++
+	}
+	return n
+}
+
+// escapeSequenceLength returns the length of the ANSI escape sequence at the
+// beginning of s, or zero if s does not start with one.
+func escapeSequenceLength(s string) int {
+	if !strings.HasPrefix(s, "\x1b[") {
+		return 0
+	}
+	if i := strings.IndexByte(s, 'm'); i >= 0 {
+		return i + 1
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	err := Wrap(Join(
+		WithTypes(New("connection refused"), "Temporary"),
+		WithTags(New("no such host"), T("host", "example.com")),
+	), "fetching user")
+
+	tests := []struct {
+		scenario string
+		err      error
+		opts     RenderOptions
+		output   string
+	}{
+		{
+			scenario: "nil error",
+			err:      nil,
+			output:   "",
+		},
+
+		{
+			scenario: "summary",
+			err:      err,
+			output:   "fetching user: connection refused; no such host",
+		},
+
+		{
+			scenario: "verbose tree",
+			err:      err,
+			opts:     RenderOptions{Verbose: true},
+			output: `fetching user
+├── connection refused (Temporary)
+└── no such host [host:"example.com"]`,
+		},
+
+		{
+			scenario: "verbose tree with colors",
+			err:      WithTypes(New("oops"), "Temporary"),
+			opts:     RenderOptions{Verbose: true, Color: true},
+			output:   "oops\x1b[36m (Temporary)\x1b[0m",
+		},
+
+		{
+			scenario: "summary wrapped at word boundaries",
+			err:      New("the quick brown fox jumps over the lazy dog"),
+			opts:     RenderOptions{MaxWidth: 15},
+			output:   "the quick brown\nfox jumps over\nthe lazy dog",
+		},
+
+		{
+			scenario: "summary with words longer than the width",
+			err:      New("unexpected character in /a/very/long/path/to/a/file"),
+			opts:     RenderOptions{MaxWidth: 10},
+			output:   "unexpected\ncharacter\nin\n/a/very/long/path/to/a/file",
+		},
+
+		{
+			scenario: "verbose lines truncated to the width",
+			err:      err,
+			opts:     RenderOptions{Verbose: true, MaxWidth: 20},
+			output: `fetching user
+├── connection re...
+└── no such host ...`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			if s := Render(test.err, test.opts); s != test.output {
+				t.Error("bad output:")
+				t.Logf("expected:\n%s", test.output)
+				t.Logf("found:\n%s", s)
+			}
+		})
+	}
+}
+
+func TestRenderStacks(t *testing.T) {
+	err := New("oops")
+
+	if s := Render(err, RenderOptions{Verbose: true}); strings.Contains(s, "TestRenderStacks") {
+		t.Error("stack traces must not be rendered unless ShowStacks is set:", s)
+	}
+
+	if s := Render(err, RenderOptions{Verbose: true, ShowStacks: true}); !strings.Contains(s, "TestRenderStacks") {
+		t.Error("stack traces must be rendered when ShowStacks is set:", s)
+	}
+
+	if s := Render(err, RenderOptions{ShowStacks: true}); s != "oops" {
+		t.Error("stack traces must not be rendered in the summary:", s)
+	}
+}
+
+func TestTruncateLineWithColors(t *testing.T) {
+	line := "oops\x1b[36m (Temporary)\x1b[0m"
+
+	if s := truncateLine(line, 17); s != line {
+		t.Errorf("lines that fit must not be truncated: %q", s)
+	}
+
+	if s := truncateLine(line, 10); s != "oops\x1b[36m (T\x1b[0m..." {
+		t.Errorf("bad truncated line: %q", s)
+	}
+}
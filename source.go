@@ -1,6 +1,32 @@
 package errors
 
-import "strings"
+import (
+	"strings"
+	"sync"
+)
+
+// sourceCache maps program counters to their resolved sources. The number of
+// entries is bounded by the size of the program's code, and program counters
+// are stable for the lifetime of the process, so entries are never evicted.
+var sourceCache sync.Map // map[uintptr]source
+
+type source struct {
+	file string
+	line int
+	name string
+}
+
+// cachedSourceForPC is like sourceForPC but caches the result, so formatting
+// the same stack traces repeatedly does not resolve their frames every time.
+func cachedSourceForPC(pc uintptr) (file string, line int, name string) {
+	if v, ok := sourceCache.Load(pc); ok {
+		s := v.(source)
+		return s.file, s.line, s.name
+	}
+	file, line, name = sourceForPC(pc)
+	sourceCache.Store(pc, source{file: file, line: line, name: name})
+	return
+}
 
 // sourceForPC returns the file and line given a program counter address.
 // The file path is in the canonical form for Go programs, starting with
@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+func TestCachedSourceForPC(t *testing.T) {
+	for _, frame := range CaptureStackTrace(0) {
+		pc := frame.pc()
+		file, line, name := sourceForPC(pc)
+
+		for i := 0; i < 2; i++ { // cache miss, then cache hit
+			f, l, n := cachedSourceForPC(pc)
+
+			if f != file || l != line || n != name {
+				t.Errorf("cached source does not match: %s:%d %s != %s:%d %s", f, l, n, file, line, name)
+			}
+		}
+	}
+}
+
+func BenchmarkFormatStackTraceRepeated(b *testing.B) {
+	err := New("oops")
+
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			fmt.Fprintf(ioutil.Discard, "%+v", err)
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sourceCache.Range(func(pc, _ interface{}) bool {
+				sourceCache.Delete(pc)
+				return true
+			})
+			fmt.Fprintf(ioutil.Discard, "%+v", err)
+		}
+	})
+}
@@ -12,7 +12,7 @@ func fileLineFunc(pc uintptr) (file string, line int, name string) {
 		f, _ := frames.Next()
 		file = f.File
 		line = f.Line
-		name = f.Func.Name()
+		name = f.Function
 	}
 
 	return
@@ -23,3 +23,12 @@ func TestSourceForPC(t *testing.T) {
 		t.Error("bad name:", name)
 	}
 }
+
+func TestFileLineFuncName(t *testing.T) {
+	pc := [1]uintptr{}
+	runtime.Callers(1, pc[:])
+
+	if _, _, name := fileLineFunc(pc[0]); name != "github.com/segmentio/errors-go.TestFileLineFuncName" {
+		t.Error("bad function name:", name)
+	}
+}
@@ -1,11 +1,17 @@
 package errors
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"math/rand"
 	"path"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 )
 
@@ -17,7 +23,10 @@ type Frame uintptr
 func (f Frame) pc() uintptr { return uintptr(f) }
 
 func (f Frame) source() (string, int, string) {
-	return sourceForPC(f.pc())
+	if d, ok := displayFrames.lookup(f); ok {
+		return d.file, d.line, d.name
+	}
+	return cachedSourceForPC(f.pc())
 }
 
 func (f Frame) file() string {
@@ -58,7 +67,9 @@ func (f Frame) Format(s fmt.State, verb rune) {
 		switch {
 		case s.Flag('#'):
 			fn := runtime.FuncForPC(pc)
-			if fn == nil {
+			if d, ok := displayFrames.lookup(f); ok {
+				fmt.Fprintf(s, "%s\n\t%s", d.name, d.file)
+			} else if fn == nil {
 				fmt.Fprintf(s, "(unknown)\n\t%#x", pc)
 			} else {
 				file, _ := fn.FileLine(pc)
@@ -110,6 +121,27 @@ func (f Frame) Format(s fmt.State, verb rune) {
 	}
 }
 
+// MarshalJSON satisfies the json.Marshaler interface, the frame is encoded as
+// an object with the function name prefixed by its full package path, the path
+// of the source file relative to the compile time GOPATH, and the line number:
+//
+//	{"func":"github.com/segmentio/errors-go.New","file":"github.com/segmentio/errors-go/errors.go","line":42}
+//
+// Frames that cannot be resolved to a function are encoded as null.
+func (f Frame) MarshalJSON() ([]byte, error) {
+	file, line, name := f.source()
+	if name == "" && file == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(jsonFrame{Func: name, File: file, Line: line})
+}
+
+type jsonFrame struct {
+	Func string `json:"func"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
 // StackTrace is stack of Frames from innermost (newest) to outermost (oldest).
 type StackTrace []Frame
 
@@ -143,6 +175,200 @@ func (st StackTrace) Format(s fmt.State, verb rune) {
 	}
 }
 
+// MarshalText satisfies the encoding.TextMarshaler interface.
+//
+// The stack trace is encoded with one line per frame, from innermost to
+// outermost, where each line has the form:
+//
+//	function\tfile:line
+//
+// The function is the name prefixed by its full package path, and the file is
+// the path of the source file relative to the compile time GOPATH, as printed
+// by the %#n and %+s verbs.
+func (st StackTrace) MarshalText() ([]byte, error) {
+	b := []byte{}
+
+	for _, f := range st {
+		file, line, name := f.source()
+		b = append(b, name...)
+		b = append(b, '\t')
+		b = append(b, file...)
+		b = append(b, ':')
+		b = strconv.AppendInt(b, int64(line), 10)
+		b = append(b, '\n')
+	}
+
+	return b, nil
+}
+
+// UnmarshalText satisfies the encoding.TextUnmarshaler interface, it decodes
+// stack traces in the format produced by MarshalText.
+//
+// Program counters are not part of the text representation, the frames of the
+// decoded stack trace can only be used to format the function names, source
+// files and line numbers that they were encoded with. The source information is
+// kept in a table of the last 65536 distinct frames decoded by the program,
+// frames evicted from this table lose their source information.
+func (st *StackTrace) UnmarshalText(b []byte) error {
+	frames := StackTrace{}
+
+	for len(b) != 0 {
+		var line []byte
+
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			line, b = b[:i], b[i+1:]
+		} else {
+			line, b = b, nil
+		}
+
+		if len(line) == 0 {
+			continue
+		}
+
+		f, err := parseFrame(string(line))
+		if err != nil {
+			return err
+		}
+
+		frames = append(frames, f)
+	}
+
+	*st = frames
+	return nil
+}
+
+// MarshalJSON satisfies the json.Marshaler interface, the stack trace is
+// encoded as an array of frames in the format documented on Frame.MarshalJSON.
+func (st StackTrace) MarshalJSON() ([]byte, error) {
+	if st == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal([]Frame(st))
+}
+
+// UnmarshalJSON satisfies the json.Unmarshaler interface, it decodes stack
+// traces in the format produced by MarshalJSON. Like with UnmarshalText, the
+// decoded frames can only be used for display purposes.
+func (st *StackTrace) UnmarshalJSON(b []byte) error {
+	var frames []*jsonFrame
+
+	if err := json.Unmarshal(b, &frames); err != nil {
+		return err
+	}
+
+	if frames == nil {
+		*st = nil
+		return nil
+	}
+
+	stack := make(StackTrace, 0, len(frames))
+
+	for _, f := range frames {
+		if f != nil {
+			stack = append(stack, displayFrames.intern(displayFrame{
+				name: f.Func,
+				file: f.File,
+				line: f.Line,
+			}))
+		}
+	}
+
+	*st = stack
+	return nil
+}
+
+func parseFrame(s string) (Frame, error) {
+	i := strings.IndexByte(s, '\t')
+	j := strings.LastIndexByte(s, ':')
+
+	if i < 0 || j < i {
+		return 0, Errorf("malformed stack frame: %q", s)
+	}
+
+	line, err := strconv.Atoi(s[j+1:])
+	if err != nil {
+		return 0, Errorf("malformed line number in stack frame: %q", s)
+	}
+
+	return displayFrames.intern(displayFrame{
+		name: s[:i],
+		file: s[i+1 : j],
+		line: line,
+	}), nil
+}
+
+// displayFrame holds the source information of frames decoded from their text
+// representation, which do not have a program counter.
+type displayFrame struct {
+	name string
+	file string
+	line int
+}
+
+// maxDisplayFrames is the maximum number of frames held by displayFrames.
+const maxDisplayFrames = 1 << 16
+
+// displayFrameTable assigns Frame values to display frames, counting down from
+// the largest uintptr so they never collide with actual program counters.
+//
+// The table only holds the last maxDisplayFrames distinct frames, so decoding
+// stack traces does not grow memory usage without bounds. Frames that were
+// evicted from the table have no source information anymore.
+type displayFrameTable struct {
+	mutex  sync.RWMutex
+	count  uintptr
+	frames []displayFrame
+	index  map[displayFrame]Frame
+}
+
+func (t *displayFrameTable) intern(d displayFrame) Frame {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if f, ok := t.index[d]; ok {
+		return f
+	}
+
+	if t.index == nil {
+		t.index = make(map[displayFrame]Frame)
+	}
+
+	n := t.count
+	t.count++
+
+	if len(t.frames) < maxDisplayFrames {
+		t.frames = append(t.frames, d)
+	} else {
+		i := n % maxDisplayFrames
+		delete(t.index, t.frames[i])
+		t.frames[i] = d
+	}
+
+	f := Frame(^n)
+	t.index[d] = f
+	return f
+}
+
+func (t *displayFrameTable) lookup(f Frame) (displayFrame, bool) {
+	n := ^uintptr(f)
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if n >= t.count {
+		return displayFrame{}, false // actual program counter
+	}
+
+	if t.count-n > uintptr(len(t.frames)) {
+		return displayFrame{}, true // evicted
+	}
+
+	return t.frames[n%maxDisplayFrames], true
+}
+
+// displayFrames is the global table of frames decoded by StackTrace.UnmarshalText.
+var displayFrames displayFrameTable
+
 func shortFuncName(name string) string {
 	name = longFuncName(name)
 	if i := strings.Index(name, "."); i >= 0 {
@@ -161,7 +387,18 @@ func longFuncName(name string) string {
 // CaptureStackTrace walks the call stack that led to this function and records
 // it as a StackTrace value. The skip argument is the number of stacks frames to
 // skip, the frame for captureStackTrace is never included in the returned trace.
+//
+// Leading frames from functions of packages registered with
+// RegisterInternalPackage are also skipped, so the trace starts at the first
+// frame of the application code.
+//
+// When a sample rate lower than 1 was set with SetStackSampleRate, the function
+// may return a nil stack trace.
 func CaptureStackTrace(skip int) StackTrace {
+	if !sampleStackTrace() {
+		return nil
+	}
+
 	frames := make([]uintptr, 100)
 	length := runtime.Callers(skip+2, frames[:])
 
@@ -177,9 +414,100 @@ func CaptureStackTrace(skip int) StackTrace {
 		completeInitialization()
 	}
 
-	return makeStackTrace(frames[:length])
+	return makeStackTrace(internalPackages.trim(frames[:length]))
 }
 
+// stackSampleRate holds the float64 value set by SetStackSampleRate, a nil
+// value means that all stack traces are captured.
+var stackSampleRate atomic.Value
+
+// SetStackSampleRate configures the fraction of calls to CaptureStackTrace that
+// record a stack trace, the other calls return nil. The rate is a value between
+// 0 (never capture stack traces) and 1 (always capture stack traces), values
+// out of this range are clamped.
+//
+// Capturing stack traces is the most expensive part of creating errors, which
+// may become significant in programs generating many errors per second. Setting
+// a sample rate keeps enough stack traces to diagnose those errors while cutting
+// the CPU cost.
+//
+// The sample rate is global to the program, the default is 1 so sampling is
+// disabled unless this function is called.
+func SetStackSampleRate(rate float64) {
+	switch {
+	case math.IsNaN(rate) || rate > 1:
+		rate = 1
+	case rate < 0:
+		rate = 0
+	}
+	stackSampleRate.Store(rate)
+}
+
+func sampleStackTrace() bool {
+	rate, ok := stackSampleRate.Load().(float64)
+	switch {
+	case !ok || rate >= 1:
+		return true
+	case rate <= 0:
+		return false
+	default:
+		return rand.Float64() < rate
+	}
+}
+
+// RegisterInternalPackage declares that functions with a name starting with
+// prefix belong to an error-wrapping helper package. Frames of those functions
+// are skipped when they appear at the top of stack traces captured by the
+// package, attributing the errors to the code that called the helpers.
+//
+// The prefix is matched against fully qualified function names, for example
+// "github.com/example/app/errutil.".
+func RegisterInternalPackage(prefix string) {
+	internalPackages.register(prefix)
+}
+
+type packagePrefixes struct {
+	mutex    sync.Mutex
+	prefixes atomic.Value // []string
+}
+
+func (p *packagePrefixes) register(prefix string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	prefixes, _ := p.prefixes.Load().([]string)
+	prefixes = append(prefixes[:len(prefixes):len(prefixes)], prefix)
+	p.prefixes.Store(prefixes)
+}
+
+func (p *packagePrefixes) match(name string) bool {
+	prefixes, _ := p.prefixes.Load().([]string)
+
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *packagePrefixes) trim(frames []uintptr) []uintptr {
+	if prefixes, _ := p.prefixes.Load().([]string); len(prefixes) == 0 {
+		return frames
+	}
+
+	for len(frames) != 0 && p.match(Frame(frames[0]).name()) {
+		frames = frames[1:]
+	}
+
+	return frames
+}
+
+// internalPackages is the global set of function name prefixes registered by
+// calls to RegisterInternalPackage.
+var internalPackages packagePrefixes
+
 func makeStackTrace(frames []uintptr) StackTrace {
 	stackTrace := make(StackTrace, len(frames))
 	for i, pc := range frames {
@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestFrameMarshalJSON(t *testing.T) {
+	f := func() StackTrace { return CaptureStackTrace(0) }
+	stack := f()
+
+	b, err := json.Marshal(stack[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var frame map[string]interface{}
+	if err := json.Unmarshal(b, &frame); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(frame) != 3 {
+		t.Error("bad number of fields in the frame:", string(b))
+	}
+
+	if name, _ := frame["func"].(string); name != fmt.Sprintf("%#n", stack[0]) {
+		t.Error("bad function name:", name)
+	}
+
+	if file, _ := frame["file"].(string); file != "github.com/segmentio/errors-go/stack_json_test.go" {
+		t.Error("bad file:", file)
+	}
+
+	if line, _ := frame["line"].(float64); int(line) != stack[0].line() {
+		t.Error("bad line:", line)
+	}
+
+	if b, _ := json.Marshal(Frame(0)); string(b) != "null" {
+		t.Error("bad encoding of an invalid frame:", string(b))
+	}
+}
+
+func TestStackTraceMarshalJSON(t *testing.T) {
+	stack := CaptureStackTrace(0)
+
+	b, err := json.Marshal(stack)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var frames []map[string]interface{}
+	if err := json.Unmarshal(b, &frames); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(frames) != len(stack) {
+		t.Fatalf("bad number of frames: %d != %d", len(frames), len(stack))
+	}
+
+	decoded := StackTrace{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if s1, s2 := fmt.Sprintf("%+v", stack), fmt.Sprintf("%+v", decoded); s1 != s2 {
+		t.Errorf("the stack trace did not round-trip:\n%s\n%s", s1, s2)
+	}
+}
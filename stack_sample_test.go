@@ -0,0 +1,55 @@
+package errors
+
+import "testing"
+
+func TestSetStackSampleRate(t *testing.T) {
+	defer SetStackSampleRate(1)
+
+	t.Run("rate=0", func(t *testing.T) {
+		SetStackSampleRate(0)
+
+		for i := 0; i < 100; i++ {
+			if stack := CaptureStackTrace(0); stack != nil {
+				t.Fatal("no stack traces must be captured when the sample rate is zero")
+			}
+		}
+
+		if stack := New("oops").(errorStackTrace).StackTrace(); stack != nil {
+			t.Error("errors must not carry stack traces when the sample rate is zero")
+		}
+	})
+
+	t.Run("rate=1", func(t *testing.T) {
+		SetStackSampleRate(1)
+
+		for i := 0; i < 100; i++ {
+			if stack := CaptureStackTrace(0); stack == nil {
+				t.Fatal("all stack traces must be captured when the sample rate is one")
+			}
+		}
+	})
+
+	t.Run("rate=0.5", func(t *testing.T) {
+		SetStackSampleRate(0.5)
+		n := 0
+
+		for i := 0; i < 1000; i++ {
+			if stack := CaptureStackTrace(0); stack != nil {
+				n++
+			}
+		}
+
+		if n == 0 || n == 1000 {
+			t.Error("stack traces must be sampled:", n)
+		}
+	})
+}
+
+func BenchmarkCaptureStackTraceSampled(b *testing.B) {
+	defer SetStackSampleRate(1)
+	SetStackSampleRate(0.01)
+
+	for i := 0; i < b.N; i++ {
+		CaptureStackTrace(0)
+	}
+}
@@ -236,3 +236,100 @@ func getwd() string {
 	path, _ := os.Getwd()
 	return path
 }
+
+func testInternalWrapHelper(err error) error {
+	return Wrap(err, "wrapped by helper")
+}
+
+func TestRegisterInternalPackage(t *testing.T) {
+	RegisterInternalPackage("github.com/segmentio/errors-go.testInternalWrapHelper")
+	defer internalPackages.prefixes.Store([]string(nil))
+
+	err := testInternalWrapHelper(New("oops")) // called from TestRegisterInternalPackage
+
+	_, _, _, stacks, _ := Inspect(err)
+
+	if len(stacks) == 0 || len(stacks[0]) == 0 {
+		t.Fatal("no stack trace found on the wrapped error")
+	}
+
+	if name := fmt.Sprintf("%n", stacks[0][0]); name != "TestRegisterInternalPackage" {
+		t.Error("bad top frame:", name)
+	}
+}
+
+func TestStackTraceMarshalText(t *testing.T) {
+	stack := CaptureStackTrace(0)
+
+	b, err := stack.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := StackTrace{}
+	if err := decoded.UnmarshalText(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded) != len(stack) {
+		t.Fatalf("bad number of frames: %d != %d", len(decoded), len(stack))
+	}
+
+	for i := range stack {
+		for _, format := range []string{"%+v", "%#n", "%n", "%s"} {
+			s1 := fmt.Sprintf(format, stack[i])
+			s2 := fmt.Sprintf(format, decoded[i])
+			if s1 != s2 {
+				t.Errorf("frame #%d: bad %s format: %q != %q", i, format, s2, s1)
+			}
+		}
+	}
+
+	b2, err := decoded.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != string(b2) {
+		t.Errorf("the text representation did not round-trip:\n%s\n%s", b, b2)
+	}
+
+	if err := decoded.UnmarshalText([]byte("no tab here\n")); err == nil {
+		t.Error("no error returned when decoding a malformed frame")
+	}
+}
+
+func TestDisplayFrameTableEviction(t *testing.T) {
+	table := &displayFrameTable{}
+	first := table.intern(displayFrame{name: "first", file: "first.go", line: 1})
+
+	if d, ok := table.lookup(first); !ok || d.name != "first" {
+		t.Error("bad display frame:", d, ok)
+	}
+
+	for i := 0; i < maxDisplayFrames; i++ {
+		table.intern(displayFrame{name: "f", file: "f.go", line: i})
+	}
+
+	if n := len(table.frames); n != maxDisplayFrames {
+		t.Error("bad number of frames in the table:", n)
+	}
+
+	if n := len(table.index); n != maxDisplayFrames {
+		t.Error("bad number of frames in the index:", n)
+	}
+
+	if d, ok := table.lookup(first); !ok || d != (displayFrame{}) {
+		t.Error("evicted frames must have no source information:", d, ok)
+	}
+
+	last := table.intern(displayFrame{name: "f", file: "f.go", line: maxDisplayFrames - 1})
+
+	if d, ok := table.lookup(last); !ok || d.line != maxDisplayFrames-1 {
+		t.Error("bad display frame:", d, ok)
+	}
+
+	if _, ok := table.lookup(CaptureStackTrace(0)[0]); ok {
+		t.Error("program counters must not be found in the table of display frames")
+	}
+}
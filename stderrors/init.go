@@ -2,5 +2,8 @@ package stderrors
 
 import (
 	_ "github.com/segmentio/errors-go/ioerrors"
+	_ "github.com/segmentio/errors-go/jsonerrors"
 	_ "github.com/segmentio/errors-go/neterrors"
+	_ "github.com/segmentio/errors-go/strconverrors"
+	_ "github.com/segmentio/errors-go/timeerrors"
 )
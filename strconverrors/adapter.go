@@ -0,0 +1,37 @@
+package strconverrors
+
+import (
+	"strconv"
+
+	errors "github.com/segmentio/errors-go"
+)
+
+// Adapt checks the type of err and if it is a *strconv.NumError, adapts it to
+// be of type "Validation", and of type "OutOfRange" as well when the parsed
+// value was out of range for the target type. The errors are tagged with the
+// name of the strconv function ("func") and the input that failed to be parsed
+// ("num").
+//
+// This function is automatically installed as a global adapter when importing
+// the strconverrors package, a program likely should use errors.Adapt instead
+// of calling this adapter directly.
+func Adapt(err error) (error, bool) {
+	if e, ok := err.(*strconv.NumError); ok {
+		return &numError{e}, true
+	}
+	return err, false
+}
+
+type numError struct{ cause *strconv.NumError }
+
+func (e *numError) Cause() error     { return e.cause }
+func (e *numError) Error() string    { return e.cause.Error() }
+func (e *numError) Validation() bool { return true }
+func (e *numError) OutOfRange() bool { return e.cause.Err == strconv.ErrRange }
+
+func (e *numError) Tags() []errors.Tag {
+	return []errors.Tag{
+		errors.T("func", e.cause.Func),
+		errors.T("num", e.cause.Num),
+	}
+}
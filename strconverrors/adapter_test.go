@@ -0,0 +1,34 @@
+package strconverrors
+
+import (
+	"strconv"
+	"testing"
+
+	errors "github.com/segmentio/errors-go"
+	"github.com/segmentio/errors-go/errorstest"
+)
+
+func TestAdapt(t *testing.T) {
+	_, syntaxError := strconv.Atoi("hello")
+	_, rangeError := strconv.ParseInt("99999999999999999999", 10, 64)
+
+	errorstest.TestAdapter(t, errors.AdapterFunc(Adapt),
+		errorstest.AdapterTest{
+			Error: syntaxError,
+			Types: []string{"Validation"},
+			Tags: []errors.Tag{
+				errors.T("func", "Atoi"),
+				errors.T("num", "hello"),
+			},
+		},
+
+		errorstest.AdapterTest{
+			Error: rangeError,
+			Types: []string{"OutOfRange", "Validation"},
+			Tags: []errors.Tag{
+				errors.T("func", "ParseInt"),
+				errors.T("num", "99999999999999999999"),
+			},
+		},
+	)
+}
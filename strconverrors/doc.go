@@ -0,0 +1,6 @@
+// Package strconverrors provides adapters for errors generated by the standard
+// strconv package.
+//
+// Importing this package installs the strconv errors adapters on the global set
+// of adapters of the parent errors-go package.
+package strconverrors
@@ -1,6 +1,11 @@
 package errors
 
-import "sort"
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
 
 // Tag is a key/value type used to represent a single error tag.
 type Tag struct {
@@ -16,6 +21,170 @@ func T(name string, value string) Tag {
 	}
 }
 
+// TInt returns a Tag value with the given name and the decimal representation
+// of v as value.
+func TInt(name string, v int64) Tag {
+	return T(name, strconv.FormatInt(v, 10))
+}
+
+// TBool returns a Tag value with the given name and either "true" or "false"
+// as value.
+func TBool(name string, v bool) Tag {
+	return T(name, strconv.FormatBool(v))
+}
+
+// TFloat returns a Tag value with the given name and the shortest decimal
+// representation of v which parses back to the same float as value.
+func TFloat(name string, v float64) Tag {
+	return T(name, strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+// tagRedactor holds the func(Tag) Tag set by SetTagRedactor.
+var tagRedactor atomic.Value
+
+// SetTagRedactor installs a function called on each tag when errors are
+// formatted or converted with ValueOf, which provides a single place to prevent
+// sensitive values from being written to logs or sent to external systems:
+//
+//	errors.SetTagRedactor(func(tag errors.Tag) errors.Tag {
+//		if tag.Name == "authorization" {
+//			tag.Value = "***"
+//		}
+//		return tag
+//	})
+//
+// The tags carried by errors and returned by functions like Tags are left
+// unchanged. Passing nil removes the redactor, which is the default.
+func SetTagRedactor(redact func(Tag) Tag) {
+	tagRedactor.Store(redact)
+}
+
+// redactTags returns the list of tags rewritten by the redactor installed with
+// SetTagRedactor, or tags if there was none.
+func redactTags(tags []Tag) []Tag {
+	redact, _ := tagRedactor.Load().(func(Tag) Tag)
+	if redact == nil || len(tags) == 0 {
+		return tags
+	}
+	redacted := make([]Tag, len(tags))
+	for i, tag := range tags {
+		redacted[i] = redact(tag)
+	}
+	return redacted
+}
+
+// tagNameNormalizer holds the func(string) string set by SetTagNameNormalizer.
+var tagNameNormalizer atomic.Value
+
+// SetTagNameNormalizer installs a function called on the names of tags when
+// they are read from errors by functions like Tags, TagsMap, or ValueOf, and
+// when errors are formatted. It provides a way to enforce a consistent naming
+// of tags across a program, including the tags set by error adapters, for
+// example:
+//
+//	errors.SetTagNameNormalizer(func(name string) string {
+//		return strings.ToLower(strings.Replace(name, "-", "_", -1))
+//	})
+//
+// Tag names passed to LookupTag, WithoutTags, RegisterHighCardinalityTags, and
+// IsHighCardinalityTag are normalized as well, so they match the tags read from
+// errors. The normalizer should be installed before calling those functions.
+// Passing nil removes the normalizer, which is the default.
+func SetTagNameNormalizer(normalize func(string) string) {
+	tagNameNormalizer.Store(normalize)
+}
+
+// normalizeTagName returns name rewritten by the normalizer installed with
+// SetTagNameNormalizer, or name if there was none.
+func normalizeTagName(name string) string {
+	if normalize, _ := tagNameNormalizer.Load().(func(string) string); normalize != nil {
+		name = normalize(name)
+	}
+	return name
+}
+
+// tagsOf returns the tags of e, with names rewritten by the normalizer
+// installed with SetTagNameNormalizer. The slice returned by e is copied before
+// being modified.
+func tagsOf(e errorTags) []Tag {
+	tags := e.Tags()
+	if normalize, _ := tagNameNormalizer.Load().(func(string) string); normalize != nil && len(tags) != 0 {
+		tags = copyTags(tags)
+		for i := range tags {
+			tags[i].Name = normalize(tags[i].Name)
+		}
+	}
+	return tags
+}
+
+// RegisterHighCardinalityTags marks the tags with the given names as having a
+// high cardinality (user ids, request ids, ...). Those tags are still carried
+// by errors and returned by Tags, but are excluded from the tags returned by
+// MetricTags to prevent them from being used as metric labels.
+func RegisterHighCardinalityTags(names ...string) {
+	normalized := make([]string, len(names))
+	for i, name := range names {
+		normalized[i] = normalizeTagName(name)
+	}
+	highCardinalityTags.register(normalized...)
+}
+
+// IsHighCardinalityTag returns true if a tag with the given name was marked as
+// having a high cardinality by a call to RegisterHighCardinalityTags.
+func IsHighCardinalityTag(name string) bool {
+	return highCardinalityTags.contains(normalizeTagName(name))
+}
+
+// MetricTags returns the tags set on err and its causes that are suitable to
+// be used as metric labels, which excludes tags marked as having a high
+// cardinality. Like DistinctTags, only one value is returned for each tag name.
+func MetricTags(err error) []Tag {
+	tags := DistinctTags(err)
+	i := 0
+
+	for _, tag := range tags {
+		if !highCardinalityTags.contains(tag.Name) {
+			tags[i] = tag
+			i++
+		}
+	}
+
+	if i == 0 {
+		return nil
+	}
+
+	return tags[:i]
+}
+
+type tagNameSet struct {
+	mutex sync.RWMutex
+	names map[string]struct{}
+}
+
+func (set *tagNameSet) register(names ...string) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	if set.names == nil {
+		set.names = make(map[string]struct{}, len(names))
+	}
+
+	for _, name := range names {
+		set.names[name] = struct{}{}
+	}
+}
+
+func (set *tagNameSet) contains(name string) bool {
+	set.mutex.RLock()
+	_, ok := set.names[name]
+	set.mutex.RUnlock()
+	return ok
+}
+
+// highCardinalityTags is the global set of tag names that the program has
+// marked as having a high cardinality by calling RegisterHighCardinalityTags.
+var highCardinalityTags tagNameSet
+
 func makeTagsMap(tags ...Tag) map[string]string {
 	if len(tags) == 0 {
 		return nil
@@ -54,15 +223,34 @@ func copyTags(tags []Tag) []Tag {
 }
 
 func deepAppendTags(tags []Tag, err error) []Tag {
-	walk(err, func(err error) {
+	walkPath(err, nil, func(err error) bool {
+		if e, ok := err.(*errorWithoutTags); ok {
+			tags = append(tags, e.maskTags(deepAppendTags(nil, e.cause))...)
+			sortTags(tags)
+			return false
+		}
 		tags = appendTags(tags, err)
+		return true
 	})
 	return tags
 }
 
+// removeTags removes the tags with names listed in names from tags, modifying
+// the slice in place.
+func removeTags(tags []Tag, names []string) []Tag {
+	i := 0
+	for _, t := range tags {
+		if !containsType(names, t.Name) {
+			tags[i] = t
+			i++
+		}
+	}
+	return tags[:i]
+}
+
 func appendTags(tags []Tag, err error) []Tag {
 	if e, ok := err.(errorTags); ok {
-		tags = append(tags, e.Tags()...)
+		tags = append(tags, tagsOf(e)...)
 	}
 	sortTags(tags)
 	return tags
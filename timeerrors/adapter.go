@@ -0,0 +1,34 @@
+package timeerrors
+
+import (
+	"time"
+
+	errors "github.com/segmentio/errors-go"
+)
+
+// Adapt checks the type of err and if it is a *time.ParseError, adapts it to be
+// of type "Validation". The errors are tagged with the "layout" and the "value"
+// that failed to be parsed.
+//
+// This function is automatically installed as a global adapter when importing
+// the timeerrors package, a program likely should use errors.Adapt instead of
+// calling this adapter directly.
+func Adapt(err error) (error, bool) {
+	if e, ok := err.(*time.ParseError); ok {
+		return &parseError{e}, true
+	}
+	return err, false
+}
+
+type parseError struct{ cause *time.ParseError }
+
+func (e *parseError) Cause() error     { return e.cause }
+func (e *parseError) Error() string    { return e.cause.Error() }
+func (e *parseError) Validation() bool { return true }
+
+func (e *parseError) Tags() []errors.Tag {
+	return []errors.Tag{
+		errors.T("layout", e.cause.Layout),
+		errors.T("value", e.cause.Value),
+	}
+}
@@ -0,0 +1,24 @@
+package timeerrors
+
+import (
+	"testing"
+	"time"
+
+	errors "github.com/segmentio/errors-go"
+	"github.com/segmentio/errors-go/errorstest"
+)
+
+func TestAdapt(t *testing.T) {
+	_, parseError := time.Parse(time.RFC3339, "2006-13-02T15:04:05Z")
+
+	errorstest.TestAdapter(t, errors.AdapterFunc(Adapt),
+		errorstest.AdapterTest{
+			Error: parseError,
+			Types: []string{"Validation"},
+			Tags: []errors.Tag{
+				errors.T("layout", time.RFC3339),
+				errors.T("value", "2006-13-02T15:04:05Z"),
+			},
+		},
+	)
+}
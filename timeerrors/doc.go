@@ -0,0 +1,6 @@
+// Package timeerrors provides adapters for errors generated by the standard
+// time package.
+//
+// Importing this package installs the time errors adapters on the global set of
+// adapters of the parent errors-go package.
+package timeerrors
@@ -1,6 +1,9 @@
 package twirperrors
 
 import (
+	"encoding/json"
+	"sort"
+
 	errors "github.com/segmentio/errors-go"
 	"github.com/twitchtv/twirp"
 )
@@ -18,6 +21,10 @@ func Adapt(err error) (error, bool) {
 	return err, false
 }
 
+// CodeTag is the name of the tag carrying the code of adapted twirp errors, for
+// example twirp_code:"not_found".
+const CodeTag = "twirp_code"
+
 type twirpError struct {
 	cause twirp.Error
 }
@@ -28,17 +35,44 @@ func (e *twirpError) Error() string { return e.cause.Error() }
 
 func (e *twirpError) Message() string { return e.cause.Msg() }
 
+// Code returns the code of the twirp error.
+func (e *twirpError) Code() twirp.ErrorCode { return e.cause.Code() }
+
+func (e *twirpError) Types() []string {
+	var types []string
+	if s := e.cause.Meta(TypesMetaKey); s != "" {
+		json.Unmarshal([]byte(s), &types)
+	}
+	return types
+}
+
+// Tags returns the code and metadata of the twirp error as a list of tags sorted
+// by name, so the output is deterministic.
 func (e *twirpError) Tags() []errors.Tag {
 	meta := e.cause.MetaMap()
-	tags := make([]errors.Tag, 0, len(meta))
+	tags := make([]errors.Tag, 0, len(meta)+1)
+	tags = append(tags, errors.Tag{
+		Name:  CodeTag,
+		Value: string(e.cause.Code()),
+	})
 
 	for name, value := range meta {
+		if name == TypesMetaKey || name == CodeTag {
+			continue
+		}
 		tags = append(tags, errors.Tag{
 			Name:  name,
 			Value: value,
 		})
 	}
 
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Name != tags[j].Name {
+			return tags[i].Name < tags[j].Name
+		}
+		return tags[i].Value < tags[j].Value
+	})
+
 	return tags
 }
 
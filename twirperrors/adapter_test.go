@@ -1,6 +1,7 @@
 package twirperrors
 
 import (
+	"reflect"
 	"testing"
 
 	errors "github.com/segmentio/errors-go"
@@ -13,86 +14,103 @@ func TestAdapt(t *testing.T) {
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.Canceled, ""),
 			Types: []string{"Canceled", "Temporary", "Timeout"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "canceled"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.Unknown, ""),
 			Types: []string{"Unknown"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "unknown"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.InvalidArgument, ""),
 			Types: []string{"InvalidArgument", "Validation"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "invalid_argument"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.DeadlineExceeded, ""),
 			Types: []string{"DeadlineExceeded", "Temporary", "Timeout"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "deadline_exceeded"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.NotFound, ""),
 			Types: []string{"NotFound"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "not_found"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.BadRoute, ""),
 			Types: []string{"BadRoute", "Validation"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "bad_route"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.AlreadyExists, ""),
 			Types: []string{"AlreadyExists", "Conflict"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "already_exists"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.PermissionDenied, ""),
 			Types: []string{"PermissionDenied"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "permission_denied"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.Unauthenticated, ""),
 			Types: []string{"Unauthenticated"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "unauthenticated"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.ResourceExhausted, ""),
 			Types: []string{"ResourceExhausted", "Temporary", "Throttled"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "resource_exhausted"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.FailedPrecondition, ""),
 			Types: []string{"FailedPrecondition"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "failed_precondition"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.Aborted, ""),
 			Types: []string{"Aborted"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "aborted"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.OutOfRange, ""),
 			Types: []string{"OutOfRange", "Validation"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "out_of_range"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.Unimplemented, ""),
 			Types: []string{"Temporary", "Unimplemented"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "unimplemented"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.Internal, ""),
 			Types: []string{"Internal", "Temporary"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "internal"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.Unavailable, ""),
 			Types: []string{"Temporary", "Unavailable"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "unavailable"}},
 		},
 
 		errorstest.AdapterTest{
 			Error: twirp.NewError(twirp.DataLoss, ""),
 			Types: []string{"DataLoss"},
+			Tags:  []errors.Tag{{Name: "twirp_code", Value: "data_loss"}},
 		},
 
 		errorstest.AdapterTest{
@@ -100,6 +118,7 @@ func TestAdapt(t *testing.T) {
 			Types: []string{"NotFound"},
 			Tags: []errors.Tag{
 				{Name: "hello", Value: "world"},
+				{Name: "twirp_code", Value: "not_found"},
 				{Name: "twitch", Value: "tv"},
 			},
 		},
@@ -108,6 +127,45 @@ func TestAdapt(t *testing.T) {
 			Error:   twirp.NewError(twirp.NotFound, "hello world!"),
 			Message: "hello world!",
 			Types:   []string{"NotFound"},
+			Tags:    []errors.Tag{{Name: "twirp_code", Value: "not_found"}},
 		},
 	)
 }
+
+func TestTagsOrder(t *testing.T) {
+	twerr := twirp.NewError(twirp.Internal, "").
+		WithMeta("zone", "us-west-2a").
+		WithMeta("app", "api").
+		WithMeta("region", "us-west-2").
+		WithMeta("host", "api-1").
+		WithMeta("env", "production")
+
+	expected := []errors.Tag{
+		{Name: "app", Value: "api"},
+		{Name: "env", Value: "production"},
+		{Name: "host", Value: "api-1"},
+		{Name: "region", Value: "us-west-2"},
+		{Name: "twirp_code", Value: "internal"},
+		{Name: "zone", Value: "us-west-2a"},
+	}
+
+	err, _ := Adapt(twerr)
+
+	for i := 0; i != 10; i++ {
+		if tags := err.(*twirpError).Tags(); !reflect.DeepEqual(tags, expected) {
+			t.Fatal("tags are not sorted:", tags)
+		}
+	}
+}
+
+func TestCode(t *testing.T) {
+	err, _ := Adapt(twirp.NewError(twirp.NotFound, "").WithMeta(CodeTag, "bad_route"))
+
+	if code := err.(interface{ Code() twirp.ErrorCode }).Code(); code != twirp.NotFound {
+		t.Error("bad twirp error code:", code)
+	}
+
+	if code := errors.LookupTag(err, CodeTag); code != "not_found" {
+		t.Error("bad twirp code tag:", code)
+	}
+}
@@ -1,16 +1,28 @@
 package twirperrors
 
 import (
+	"encoding/json"
 	"strings"
 
 	errors "github.com/segmentio/errors-go"
 	"github.com/twitchtv/twirp"
 )
 
+// TypesMetaKey is the key of the twirp error metadata where New stores the
+// types of the original error, encoded as a JSON array of strings. Adapt uses
+// it to restore the types of errors received from remote services, including
+// the types that do not map to a twirp error code.
+const TypesMetaKey = "__types"
+
 // New constructs a twirp error from another error. The error code is guessed by
 // inspecting the types of err, and defaults to twirp.Unknown if the error had
 // no types.
 //
+// The tags of err are set as metadata of the returned error, except CodeTag
+// which would be redundant with the error code. The complete list
+// of types of err, as returned by errors.Types, is encoded in the metadata under
+// TypesMetaKey, so types of the causes of err survive the conversion as well.
+//
 // If err is nil the function returns nil.
 func New(err error) twirp.Error {
 	if err == nil {
@@ -22,85 +34,92 @@ func New(err error) twirp.Error {
 	}
 
 	msgs, types, tags, _, _ := errors.Inspect(err)
+	allTypes := errors.Types(err)
 
 	for _, typ := range types {
 		switch typ {
 		case "Canceled":
-			return newError(twirp.Canceled, msgs, tags)
+			return newError(twirp.Canceled, msgs, allTypes, tags)
 
 		case "Unknown":
-			return newError(twirp.Unknown, msgs, tags)
+			return newError(twirp.Unknown, msgs, allTypes, tags)
 
 		case "InvalidArgument":
-			return newError(twirp.InvalidArgument, msgs, tags)
+			return newError(twirp.InvalidArgument, msgs, allTypes, tags)
 
 		case "DeadlineExceeded":
-			return newError(twirp.DeadlineExceeded, msgs, tags)
+			return newError(twirp.DeadlineExceeded, msgs, allTypes, tags)
 
 		case "NotFound":
-			return newError(twirp.NotFound, msgs, tags)
+			return newError(twirp.NotFound, msgs, allTypes, tags)
 
 		case "BadRoute":
-			return newError(twirp.BadRoute, msgs, tags)
+			return newError(twirp.BadRoute, msgs, allTypes, tags)
 
 		case "AlreadyExists":
-			return newError(twirp.AlreadyExists, msgs, tags)
+			return newError(twirp.AlreadyExists, msgs, allTypes, tags)
 
 		case "PermissionDenied":
-			return newError(twirp.PermissionDenied, msgs, tags)
+			return newError(twirp.PermissionDenied, msgs, allTypes, tags)
 
 		case "Unauthenticated":
-			return newError(twirp.Unauthenticated, msgs, tags)
+			return newError(twirp.Unauthenticated, msgs, allTypes, tags)
 
 		case "ResourceExhausted":
-			return newError(twirp.ResourceExhausted, msgs, tags)
+			return newError(twirp.ResourceExhausted, msgs, allTypes, tags)
 
 		case "FailedPrecondition":
-			return newError(twirp.FailedPrecondition, msgs, tags)
+			return newError(twirp.FailedPrecondition, msgs, allTypes, tags)
 
 		case "Aborted":
-			return newError(twirp.Aborted, msgs, tags)
+			return newError(twirp.Aborted, msgs, allTypes, tags)
 
 		case "OutOfRange":
-			return newError(twirp.OutOfRange, msgs, tags)
+			return newError(twirp.OutOfRange, msgs, allTypes, tags)
 
 		case "Unimplemented":
-			return newError(twirp.Unimplemented, msgs, tags)
+			return newError(twirp.Unimplemented, msgs, allTypes, tags)
 
 		case "Internal":
-			return newError(twirp.Internal, msgs, tags)
+			return newError(twirp.Internal, msgs, allTypes, tags)
 
 		case "Unavailable":
-			return newError(twirp.Unavailable, msgs, tags)
+			return newError(twirp.Unavailable, msgs, allTypes, tags)
 
 		case "DataLoss":
-			return newError(twirp.DataLoss, msgs, tags)
+			return newError(twirp.DataLoss, msgs, allTypes, tags)
 		}
 	}
 
 	for _, typ := range types {
 		switch typ {
 		case "Validation":
-			return newError(twirp.InvalidArgument, msgs, tags)
+			return newError(twirp.InvalidArgument, msgs, allTypes, tags)
 
 		case "Timeout":
-			return newError(twirp.DeadlineExceeded, msgs, tags)
+			return newError(twirp.DeadlineExceeded, msgs, allTypes, tags)
 
 		case "Throttled":
-			return newError(twirp.ResourceExhausted, msgs, tags)
+			return newError(twirp.ResourceExhausted, msgs, allTypes, tags)
 
 		case "Conflict":
-			return newError(twirp.AlreadyExists, msgs, tags)
+			return newError(twirp.AlreadyExists, msgs, allTypes, tags)
 		}
 	}
 
-	return newError(twirp.Unknown, msgs, tags)
+	return newError(twirp.Unknown, msgs, allTypes, tags)
 }
 
-func newError(code twirp.ErrorCode, msgs []string, tags []errors.Tag) twirp.Error {
+func newError(code twirp.ErrorCode, msgs []string, types []string, tags []errors.Tag) twirp.Error {
 	twerr := twirp.NewError(code, strings.Join(msgs, ": "))
 	for _, tag := range tags {
-		twerr = twerr.WithMeta(tag.Name, tag.Value)
+		if tag.Name != CodeTag {
+			twerr = twerr.WithMeta(tag.Name, tag.Value)
+		}
+	}
+	if len(types) != 0 {
+		b, _ := json.Marshal(types)
+		twerr = twerr.WithMeta(TypesMetaKey, string(b))
 	}
 	return twerr
 }
@@ -163,12 +163,69 @@ func TestNew(t *testing.T) {
 				t.Error("wrong error code:", code)
 			}
 
-			if meta := twerr.MetaMap(); !reflect.DeepEqual(meta, map[string]string{
+			expected := map[string]string{
 				"hello":  "world",
 				"twitch": "tv",
-			}) {
+			}
+
+			if len(test.types) != 0 {
+				expected[TypesMetaKey] = `["` + strings.Join(test.types, `","`) + `"]`
+			}
+
+			if meta := twerr.MetaMap(); !reflect.DeepEqual(meta, expected) {
 				t.Error("wrong meta map:", meta)
 			}
 		})
 	}
 }
+
+func TestTypesRoundTrip(t *testing.T) {
+	err := errors.WithTags(
+		errors.WithTypes(errors.New("oops"), "NotFound", "ExpiredToken"),
+		errors.T("hello", "world"),
+	)
+
+	adapted, ok := Adapt(New(err))
+	if !ok {
+		t.Fatal("the twirp error was not adapted")
+	}
+
+	for _, typ := range []string{"NotFound", "ExpiredToken"} {
+		if !errors.Is(typ, adapted) {
+			t.Errorf("the %q type did not survive the round trip", typ)
+		}
+	}
+
+	if tags := errors.Tags(adapted); !reflect.DeepEqual(tags, []errors.Tag{
+		{Name: "hello", Value: "world"},
+		{Name: CodeTag, Value: "not_found"},
+	}) {
+		t.Error("wrong tags:", tags)
+	}
+
+	if meta := New(adapted).MetaMap(); meta[CodeTag] != "" {
+		t.Error("the code tag must not be set in the metadata of twirp errors:", meta)
+	}
+}
+
+func TestTypesOfCausesRoundTrip(t *testing.T) {
+	err := errors.WithTypes(
+		errors.Wrap(errors.Join(
+			errors.WithTypes(errors.New("token expired"), "ExpiredToken"),
+			errors.New("other"),
+		), "lookup failed"),
+		"NotFound",
+	)
+
+	twerr := New(err)
+
+	if code := twerr.Code(); code != twirp.NotFound {
+		t.Error("wrong error code:", code)
+	}
+
+	adapted, _ := Adapt(twerr)
+
+	if types := errors.Types(adapted); !reflect.DeepEqual(types, []string{"ExpiredToken", "NotFound"}) {
+		t.Error("wrong error types:", types)
+	}
+}
@@ -3,15 +3,104 @@ package errors
 import (
 	"reflect"
 	"sort"
+	"sync/atomic"
 )
 
-func deepAppendTypes(types []string, err error) []string {
+// typesCache is embedded in the internal error types of this package to
+// memoize the types of errors which are only made of internal error types.
+// Those errors are immutable so their types never change once computed.
+type typesCache struct {
+	value atomic.Value // *cachedTypesValue
+}
+
+type cachedTypesValue struct {
+	types []string
+	valid bool // false if the error has causes that are not internal
+}
+
+func (c *typesCache) cache() *typesCache { return c }
+
+type typesCacher interface {
+	cache() *typesCache
+}
+
+// cachedTypes returns the types of err if err is an internal error which only
+// has internal errors as causes, computing and memoizing them on first use.
+func cachedTypes(err error) ([]string, bool) {
+	e, ok := err.(typesCacher)
+	if !ok {
+		return nil, false
+	}
+
+	c := e.cache()
+
+	if v, _ := c.value.Load().(*cachedTypesValue); v != nil {
+		return v.types, v.valid
+	}
+
+	v := &cachedTypesValue{valid: true}
+
 	walk(err, func(err error) {
+		if !isInternalError(err) {
+			v.valid = false
+		}
+	})
+
+	if v.valid {
+		v.types = deepAppendTypes(nil, err)
+	}
+
+	c.value.Store(v)
+	return v.types, v.valid
+}
+
+// highLevelTypes is the list of types that are derived from more specific
+// types by the adapters of this package.
+var highLevelTypes = []string{
+	"Conflict",
+	"NotFound",
+	"PermissionDenied",
+	"Temporary",
+	"Throttled",
+	"Timeout",
+	"Unauthenticated",
+	"Validation",
+}
+
+func containsType(types []string, typ string) bool {
+	for _, t := range types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func deepAppendTypes(types []string, err error) []string {
+	walkPath(err, nil, func(err error) bool {
+		if e, ok := err.(*errorWithoutTypes); ok {
+			types = append(types, removeTypes(deepAppendTypes(nil, e.cause), e.types)...)
+			return false
+		}
 		types = appendTypes(types, err)
+		return true
 	})
 	return dedupeTypes(types)
 }
 
+// removeTypes removes the types listed in remove from types, modifying the
+// slice in place.
+func removeTypes(types []string, remove []string) []string {
+	i := 0
+	for _, t := range types {
+		if !containsType(remove, t) {
+			types[i] = t
+			i++
+		}
+	}
+	return types[:i]
+}
+
 func appendTypes(types []string, err error) []string {
 	if e, ok := err.(errorTypes); ok {
 		types = append(types, e.Types()...)
@@ -24,7 +113,7 @@ func appendTypes(types []string, err error) []string {
 		mt := t.Method(i)
 		mv := v.Method(i)
 
-		if f, ok := mv.Interface().(func() bool); ok && f() {
+		if f, ok := mv.Interface().(func() bool); ok && callTypeMethod(f) {
 			types = append(types, mt.Name)
 		}
 	}
@@ -32,6 +121,70 @@ func appendTypes(types []string, err error) []string {
 	return types
 }
 
+func hasType(typ string, err error) bool {
+	if e, ok := err.(errorTypes); ok {
+		for _, t := range e.Types() {
+			if t == typ {
+				return true
+			}
+		}
+	}
+
+	if f := typeMethod(typ, err); f != nil {
+		return callTypeMethod(f)
+	}
+
+	return false
+}
+
+// typeMethod returns the method of err implementing typ, or nil if err has no
+// such method.
+//
+// The Timeout and Temporary types are the most commonly tested, so the function
+// uses type assertions to lookup those methods, which is faster than using
+// reflection.
+func typeMethod(typ string, err error) func() bool {
+	switch typ {
+	case "Timeout":
+		if e, ok := err.(interface {
+			Timeout() bool
+		}); ok {
+			return e.Timeout
+		}
+		return nil
+
+	case "Temporary":
+		if e, ok := err.(interface {
+			Temporary() bool
+		}); ok {
+			return e.Temporary
+		}
+		return nil
+	}
+	return reflectTypeMethod(typ, err)
+}
+
+func reflectTypeMethod(typ string, err error) func() bool {
+	if m := reflect.ValueOf(err).MethodByName(typ); m.IsValid() {
+		if f, ok := m.Interface().(func() bool); ok {
+			return f
+		}
+	}
+	return nil
+}
+
+// callTypeMethod calls f, which is a method of an error discovered by
+// reflection, and reports false if the method panicked, so misbehaving errors
+// cannot crash the functions that classify errors.
+func callTypeMethod(f func() bool) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	return f()
+}
+
 func copyTypes(types []string) []string {
 	if len(types) == 0 {
 		return nil
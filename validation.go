@@ -0,0 +1,47 @@
+package errors
+
+// FieldError describes a problem found while validating the value of a field,
+// see Validation.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Validation returns an error aggregating the problems found while validating
+// an input, one for each of fields. The causes of the returned error are of type
+// Validation and tagged with the name of their field, and share a capture of
+// the stack trace. If fields is empty the function returns nil.
+//
+//	err = errors.Validation(
+//		errors.FieldError{Field: "email", Message: "missing @"},
+//		errors.FieldError{Field: "age", Message: "must be positive"},
+//	)
+//
+// Because all its causes are of type Validation, Is("Validation", err) returns
+// true for the returned error.
+func Validation(fields ...FieldError) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	stack := CaptureStackTrace(1)
+	e := &multiError{
+		errors: make([]error, len(fields)),
+	}
+
+	for i, f := range fields {
+		e.errors[i] = &errorWithTags{
+			cause: &errorWithTypes{
+				cause: &baseError{
+					msg:   f.Message,
+					stack: stack,
+					meta:  captureMetadata(),
+				},
+				types: []string{"Validation"},
+			},
+			tags: makeTags(T("field", f.Field)),
+		}
+	}
+
+	return e
+}
@@ -1,8 +1,14 @@
 package errors
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 // Value is a serializable error representation which carries all rich
@@ -17,42 +23,174 @@ type Value struct {
 	Types   []string
 	Stack   []string
 	Causes  []Value
+
+	// RetryAfter is the duration that a program should wait before retrying
+	// the operation that caused the error, zero if it was not set.
+	RetryAfter time.Duration `json:",omitempty"`
+
+	// Metadata carries the entries configured with SetValueMetadata, it is
+	// only set on the root value returned by ValueOf.
+	Metadata map[string]string `json:",omitempty"`
+}
+
+// valueMetadata holds the map[string]string set by SetValueMetadata.
+var valueMetadata atomic.Value
+
+// SetValueMetadata configures entries that get embedded in the Metadata field
+// of every value returned by ValueOf, for example the name and version of the
+// program producing the errors. The entries only appear on the root value and
+// not on the values of its causes.
+//
+// Passing an empty or nil map disables the feature, which is the default.
+func SetValueMetadata(metadata map[string]string) {
+	var m map[string]string
+
+	if len(metadata) != 0 {
+		m = make(map[string]string, len(metadata))
+		for k, v := range metadata {
+			m[k] = v
+		}
+	}
+
+	valueMetadata.Store(m)
+}
+
+// valueStackPathRedactor holds the func(string) string set by
+// SetValueStackPathRedactor.
+var valueStackPathRedactor atomic.Value
+
+// SetValueStackPathRedactor installs a function that ValueOf calls on the file
+// path of each stack frame, which can be used to avoid leaking information
+// about the environment where the program was built when values are sent to
+// external systems. The function names and line numbers are left unchanged.
+//
+// For example, this call configures ValueOf to only keep the base file names:
+//
+//	errors.SetValueStackPathRedactor(path.Base)
+//
+// Passing nil removes the redactor, which is the default.
+func SetValueStackPathRedactor(redact func(string) string) {
+	valueStackPathRedactor.Store(redact)
 }
 
 // ValueOf returns an error value representing err. If err is nil the function
 // returns the zero-value of Value.
 func ValueOf(err error) Value {
+	return ValueOfLimited(err, ValueOptions{})
+}
+
+// ValueOptions configures the limits applied by ValueOfLimited to the values it
+// produces. Zero fields mean no limits.
+type ValueOptions struct {
+	// Maximum number of causes of each value.
+	MaxCauses int
+
+	// Maximum depth of the tree of values, the causes of values at this depth
+	// are dropped.
+	MaxDepth int
+
+	// Maximum number of stack frames of each value.
+	MaxStackFrames int
+}
+
+// ValueTruncatedTag is the name of the tag set by ValueOfLimited on values that
+// were truncated. The tag value is a comma-separated list of entries of the
+// form "causes=N", "depth=N", or "frames=N", indicating how many causes were
+// dropped because of the MaxCauses or MaxDepth limits, and how many stack
+// frames were dropped because of the MaxStackFrames limit.
+const ValueTruncatedTag = "__truncated"
+
+// ValueOfLimited is similar to ValueOf but truncates the produced tree of values
+// according to opts, which bounds the size of values constructed from errors
+// with a large number of causes, for example before sending them over the
+// network.
+func ValueOfLimited(err error, opts ValueOptions) Value {
 	if err == nil {
 		return Value{}
 	}
 
+	v := valueOf(err, opts, nil)
+
+	if m, _ := valueMetadata.Load().(map[string]string); len(m) != 0 {
+		v.Metadata = make(map[string]string, len(m))
+		for k, x := range m {
+			v.Metadata[k] = x
+		}
+	}
+
+	return v
+}
+
+func valueOf(err error, opts ValueOptions, path errorPath) Value {
 	msgs, types, tags, stacks, causes := Inspect(err)
+	depth := len(path)
+	path = path.appendChain(err)
+	causes = path.exclude(causes)
+	truncated := []string(nil)
 
 	v := Value{
-		Message: strings.Join(msgs, ": "),
-		Types:   types,
-		Tags:    makeTagsMap(tags...),
+		Message:    strings.Join(msgs, ": "),
+		Types:      types,
+		Tags:       makeTagsMap(redactTags(tags)...),
+		RetryAfter: inspectRetryAfter(err),
 	}
 
 	if len(stacks) != 0 {
+		redact, _ := valueStackPathRedactor.Load().(func(string) string)
 		v.Stack = make([]string, 0, len(stacks[0])*len(stacks))
+		frames, dropped := 0, 0
 
 		for i, stack := range stacks {
+			if opts.MaxStackFrames > 0 {
+				if n := opts.MaxStackFrames - frames; len(stack) > n {
+					dropped += len(stack) - n
+					stack = stack[:n]
+				}
+				if len(stack) == 0 {
+					continue
+				}
+			}
 			if i != 0 {
 				v.Stack = append(v.Stack, "")
 			}
+			frames += len(stack)
 			for _, frame := range stack {
-				v.Stack = append(v.Stack, fmt.Sprintf("%+v:%n", frame, frame))
+				if redact != nil {
+					v.Stack = append(v.Stack, fmt.Sprintf("%s:%d:%n", redact(fmt.Sprintf("%+s", frame)), frame, frame))
+				} else {
+					v.Stack = append(v.Stack, fmt.Sprintf("%+v:%n", frame, frame))
+				}
 			}
 		}
+
+		if dropped != 0 {
+			truncated = append(truncated, "frames="+strconv.Itoa(dropped))
+		}
+	}
+
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth && len(causes) != 0 {
+		truncated = append(truncated, "depth="+strconv.Itoa(len(causes)))
+		causes = nil
+	}
+
+	if opts.MaxCauses > 0 && len(causes) > opts.MaxCauses {
+		truncated = append(truncated, "causes="+strconv.Itoa(len(causes)-opts.MaxCauses))
+		causes = causes[:opts.MaxCauses]
 	}
 
 	if len(causes) != 0 {
 		v.Causes = make([]Value, len(causes))
 
 		for i, cause := range causes {
-			v.Causes[i] = ValueOf(cause)
+			v.Causes[i] = valueOf(cause, opts, path)
+		}
+	}
+
+	if len(truncated) != 0 {
+		if v.Tags == nil {
+			v.Tags = make(map[string]string, 1)
 		}
+		v.Tags[ValueTruncatedTag] = strings.Join(truncated, ",")
 	}
 
 	return v
@@ -77,10 +215,11 @@ func (v Value) Err() error {
 	}
 
 	e := &errorValue{
-		msg:   v.Message,
-		types: copyTypes(v.Types),
-		tags:  makeTagsFromMap(v.Tags),
-		stack: CaptureStackTrace(1),
+		msg:        v.Message,
+		types:      copyTypes(v.Types),
+		tags:       makeTagsFromMap(v.Tags),
+		stack:      CaptureStackTrace(1),
+		retryAfter: v.RetryAfter,
 	}
 
 	if len(v.Causes) != 0 {
@@ -94,18 +233,339 @@ func (v Value) Err() error {
 	return e
 }
 
+// String returns a representation of v on a single line, in the same format as
+// Oneline, for example:
+//
+//	answer 42 (Temporary) [env:"production"] <- {A; B (Timeout)}
+//
+// If v is the zero-value, the method returns an empty string.
+func (v Value) String() string {
+	if v.IsNil() {
+		return ""
+	}
+	b := &bytes.Buffer{}
+	writeValue(b, v)
+	return b.String()
+}
+
+func writeValue(b *bytes.Buffer, v Value) {
+	if msg := v.Message; len(msg) != 0 {
+		b.WriteString(strings.Replace(msg, "\n", `\n`, -1))
+	} else {
+		b.WriteString(".")
+	}
+
+	writeTypes(b, v.Types)
+	writeTags(b, makeTagsFromMap(v.Tags))
+
+	if len(v.Causes) == 0 {
+		return
+	}
+
+	group := len(v.Causes) > 1
+	b.WriteString(" <- ")
+
+	if group {
+		b.WriteString("{")
+	}
+
+	for i, cause := range v.Causes {
+		if i != 0 {
+			b.WriteString("; ")
+		}
+		writeValue(b, cause)
+	}
+
+	if group {
+		b.WriteString("}")
+	}
+}
+
 // IsNil returns true if v represents a nil error (which means it is the
 // zero-value).
 func (v Value) IsNil() bool {
-	return v.Message == "" && v.Tags == nil && v.Types == nil && v.Stack == nil && v.Causes == nil
+	return v.Message == "" && v.Tags == nil && v.Types == nil && v.Stack == nil && v.Causes == nil && v.RetryAfter == 0 && v.Metadata == nil
+}
+
+// MarshalBinary satisfies the encoding.BinaryMarshaler interface. The binary
+// form is more compact and faster to encode and decode than JSON, which makes
+// it a better fit for high-throughput transports.
+func (v Value) MarshalBinary() ([]byte, error) {
+	return appendBinaryValue([]byte{binaryValueVersion}, v), nil
+}
+
+// UnmarshalBinary satisfies the encoding.BinaryUnmarshaler interface, it
+// decodes values encoded by MarshalBinary.
+func (v *Value) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 || b[0] != binaryValueVersion {
+		return errBinaryValue
+	}
+	d := binaryDecoder{b: b[1:]}
+	val := d.value()
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.b) != 0 {
+		return errBinaryValue
+	}
+	*v = val
+	return nil
+}
+
+// binaryValueVersion is the first byte of values encoded by MarshalBinary, it
+// leaves room for changing the encoding in the future.
+const binaryValueVersion = 1
+
+var errBinaryValue = NewNoStack("malformed binary representation of an error value")
+
+func appendBinaryValue(b []byte, v Value) []byte {
+	b = appendBinaryString(b, v.Message)
+	b = appendBinaryMap(b, v.Tags)
+	b = appendBinaryStrings(b, v.Types)
+	b = appendBinaryStrings(b, v.Stack)
+	b = appendBinaryVarint(b, int64(v.RetryAfter))
+	b = appendBinaryMap(b, v.Metadata)
+	b = appendBinaryUvarint(b, uint64(len(v.Causes)))
+	for _, cause := range v.Causes {
+		b = appendBinaryValue(b, cause)
+	}
+	return b
+}
+
+func appendBinaryUvarint(b []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], x)]...)
+}
+
+func appendBinaryVarint(b []byte, x int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], x)]...)
+}
+
+func appendBinaryString(b []byte, s string) []byte {
+	b = appendBinaryUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendBinaryStrings(b []byte, list []string) []byte {
+	b = appendBinaryUvarint(b, uint64(len(list)))
+	for _, s := range list {
+		b = appendBinaryString(b, s)
+	}
+	return b
+}
+
+func appendBinaryMap(b []byte, m map[string]string) []byte {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	b = appendBinaryUvarint(b, uint64(len(keys)))
+	for _, k := range keys {
+		b = appendBinaryString(b, k)
+		b = appendBinaryString(b, m[k])
+	}
+	return b
+}
+
+// binaryDecoder decodes values encoded by MarshalBinary, the first error is
+// recorded in the err field and stops the decoding.
+type binaryDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *binaryDecoder) value() Value {
+	v := Value{
+		Message:    d.string(),
+		Tags:       d.stringMap(),
+		Types:      d.strings(),
+		Stack:      d.strings(),
+		RetryAfter: time.Duration(d.varint()),
+		Metadata:   d.stringMap(),
+	}
+	if n := d.length(); n != 0 {
+		v.Causes = make([]Value, n)
+		for i := range v.Causes {
+			v.Causes[i] = d.value()
+		}
+	}
+	return v
+}
+
+func (d *binaryDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	x, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = errBinaryValue
+		return 0
+	}
+	d.b = d.b[n:]
+	return x
+}
+
+func (d *binaryDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	x, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.err = errBinaryValue
+		return 0
+	}
+	d.b = d.b[n:]
+	return x
+}
+
+// length decodes the length of a string or a list, which cannot be greater than
+// the number of bytes left to decode since each element takes at least a byte.
+func (d *binaryDecoder) length() int {
+	n := d.uvarint()
+	if n > uint64(len(d.b)) {
+		d.err = errBinaryValue
+		return 0
+	}
+	return int(n)
+}
+
+func (d *binaryDecoder) string() string {
+	n := d.length()
+	if d.err != nil {
+		return ""
+	}
+	s := string(d.b[:n])
+	d.b = d.b[n:]
+	return s
+}
+
+func (d *binaryDecoder) strings() []string {
+	n := d.length()
+	if n == 0 {
+		return nil
+	}
+	list := make([]string, n)
+	for i := range list {
+		list[i] = d.string()
+	}
+	return list
+}
+
+func (d *binaryDecoder) stringMap() map[string]string {
+	n := d.length()
+	if n == 0 {
+		return nil
+	}
+	m := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		k := d.string()
+		m[k] = d.string()
+	}
+	return m
+}
+
+// EqualOption is the type of options that can be passed to Value.Equal to
+// configure how values are compared.
+type EqualOption func(*equalConfig)
+
+type equalConfig struct {
+	ignoreStacks bool
+}
+
+// IgnoreStacks returns an option which configures Value.Equal to ignore the
+// stack traces of the values, which is useful in tests since stack traces vary
+// across compiler versions and builds.
+func IgnoreStacks() EqualOption {
+	return func(c *equalConfig) { c.ignoreStacks = true }
+}
+
+// Equal returns true if v and other are structurally equal, including their
+// trees of causes. Nil and empty fields are considered equal.
+func (v Value) Equal(other Value, opts ...EqualOption) bool {
+	config := equalConfig{}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return equalValues(v, other, &config)
+}
+
+func equalValues(v1, v2 Value, config *equalConfig) bool {
+	if v1.Message != v2.Message || v1.RetryAfter != v2.RetryAfter {
+		return false
+	}
+
+	if !equalStrings(v1.Types, v2.Types) || !equalStringMaps(v1.Tags, v2.Tags) || !equalStringMaps(v1.Metadata, v2.Metadata) {
+		return false
+	}
+
+	if !config.ignoreStacks && !equalStrings(v1.Stack, v2.Stack) {
+		return false
+	}
+
+	if len(v1.Causes) != len(v2.Causes) {
+		return false
+	}
+
+	for i := range v1.Causes {
+		if !equalValues(v1.Causes[i], v2.Causes[i], config) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalStringMaps(m1, m2 map[string]string) bool {
+	if len(m1) != len(m2) {
+		return false
+	}
+	for k, v1 := range m1 {
+		if v2, ok := m2[k]; !ok || v1 != v2 {
+			return false
+		}
+	}
+	return true
+}
+
+// inspectRetryAfter returns the first positive retry-after duration found on
+// the path of causes of err that Inspect follows.
+func inspectRetryAfter(err error) time.Duration {
+	var path errorPath
+
+	for err != nil && !path.contains(err) {
+		path = append(path, err)
+
+		if d := retryAfter(err); d > 0 {
+			return d
+		}
+
+		switch e := err.(type) {
+		case errorCauses:
+			return 0
+
+		case errorCause:
+			err = e.Cause()
+
+		default:
+			return 0
+		}
+	}
+
+	return 0
 }
 
 type errorValue struct {
-	msg    string
-	causes []error
-	types  []string
-	tags   []Tag
-	stack  StackTrace
+	typesCache
+	msg        string
+	causes     []error
+	types      []string
+	tags       []Tag
+	stack      StackTrace
+	retryAfter time.Duration
 }
 
 func (e *errorValue) Error() string {
@@ -132,6 +592,10 @@ func (e *errorValue) StackTrace() StackTrace {
 	return e.stack
 }
 
+func (e *errorValue) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
 func (e *errorValue) Format(s fmt.State, v rune) {
 	format(s, v, e)
 }
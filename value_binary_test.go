@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestValueMarshalBinary(t *testing.T) {
+	val := ValueOf(WithRetryAfter(WithTags(Join(
+		WithTypes(New("A"), "Timeout"),
+		Wrap(New("B"), "context"),
+	), T("env", "prod")), time.Second))
+
+	b, err := val.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Value
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if !decoded.Equal(val) {
+		t.Error("values mismatch after a binary round-trip:")
+		t.Logf("expected: %#v", val)
+		t.Logf("found:    %#v", decoded)
+	}
+
+	if err := decoded.UnmarshalBinary([]byte("garbage")); err == nil {
+		t.Error("decoding an invalid binary value did not return an error")
+	}
+
+	if err := decoded.UnmarshalBinary(b[:len(b)-1]); err == nil {
+		t.Error("decoding a truncated binary value did not return an error")
+	}
+
+	if err := (&Value{}).UnmarshalBinary(append(b, 0)); err == nil {
+		t.Error("decoding a binary value with trailing bytes did not return an error")
+	}
+}
+
+func benchmarkValue() Value {
+	errs := make([]error, 10)
+	for i := range errs {
+		errs[i] = WithTags(WithTypes(New(fmt.Sprint(i)), "Timeout"), T("index", fmt.Sprint(i)))
+	}
+	return ValueOf(Wrap(Join(errs...), "batch failed"))
+}
+
+func BenchmarkValueMarshalJSON(b *testing.B) {
+	val := benchmarkValue()
+	var size int
+
+	for i := 0; i < b.N; i++ {
+		data, _ := json.Marshal(val)
+		var v Value
+		json.Unmarshal(data, &v)
+		size = len(data)
+	}
+
+	b.ReportMetric(float64(size), "bytes/value")
+}
+
+func BenchmarkValueMarshalBinary(b *testing.B) {
+	val := benchmarkValue()
+	var size int
+
+	for i := 0; i < b.N; i++ {
+		data, _ := val.MarshalBinary()
+		var v Value
+		v.UnmarshalBinary(data)
+		size = len(data)
+	}
+
+	b.ReportMetric(float64(size), "bytes/value")
+}
+
+func TestValueMarshalJSON(t *testing.T) {
+	b, err := json.Marshal(ValueOf(Wrap(New("A"), "B")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []string{`"RetryAfter"`, `"Metadata"`} {
+		if bytes.Contains(b, []byte(field)) {
+			t.Errorf("unexpected %s field in the JSON representation of a value: %s", field, b)
+		}
+	}
+
+	SetValueMetadata(map[string]string{"service": "test"})
+	defer SetValueMetadata(nil)
+
+	b, _ = json.Marshal(ValueOf(WithRetryAfter(New("A"), time.Second)))
+
+	for _, field := range []string{`"RetryAfter":1000000000`, `"Metadata":{"service":"test"}`} {
+		if !bytes.Contains(b, []byte(field)) {
+			t.Errorf("missing %s field in the JSON representation of a value: %s", field, b)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package errors
+
+import "testing"
+
+func TestValueString(t *testing.T) {
+	tests := []struct {
+		scenario string
+		val      Value
+		str      string
+	}{
+		{
+			scenario: "zero value",
+			val:      Value{},
+			str:      "",
+		},
+
+		{
+			scenario: "message only",
+			val:      Value{Message: "oops"},
+			str:      "oops",
+		},
+
+		{
+			scenario: "message with types and tags",
+			val: Value{
+				Message: "oops",
+				Types:   []string{"Temporary", "Timeout"},
+				Tags:    map[string]string{"region": "us-west-2", "env": "prod"},
+			},
+			str: `oops (Temporary Timeout) [env:"prod" region:"us-west-2"]`,
+		},
+
+		{
+			scenario: "multi-line message",
+			val:      Value{Message: "hello\nworld"},
+			str:      `hello\nworld`,
+		},
+
+		{
+			scenario: "causes",
+			val: Value{
+				Message: "batch failed",
+				Tags:    map[string]string{"batch": "1"},
+				Causes: []Value{
+					{Message: "A"},
+					{Message: "B", Types: []string{"Timeout"}},
+				},
+			},
+			str: `batch failed [batch:"1"] <- {A; B (Timeout)}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			if s := test.val.String(); s != test.str {
+				t.Error("bad string representation of the value:")
+				t.Log("expected:", test.str)
+				t.Log("found:   ", s)
+			}
+		})
+	}
+}
+
+func TestValueStringMatchesOneline(t *testing.T) {
+	err := WithTags(Join(New("A"), WithTypes(New("B"), "Timeout")), T("env", "prod"))
+
+	if s1, s2 := ValueOf(err).String(), Oneline(err); s1 != s2 {
+		t.Error("the string representation of the value does not match the error:")
+		t.Log("expected:", s2)
+		t.Log("found:   ", s1)
+	}
+}
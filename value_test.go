@@ -152,3 +152,155 @@ func stripRuntimeStackFrames(v *Value) {
 		stripRuntimeStackFrames(&v.Causes[i])
 	}
 }
+
+func TestValueMetadata(t *testing.T) {
+	defer SetValueMetadata(nil)
+
+	metadata := map[string]string{
+		"service": "checkout-service",
+		"version": "v1.4.2",
+	}
+	SetValueMetadata(metadata)
+	metadata["version"] = "v2.0.0" // the package must hold a copy
+
+	val := ValueOf(Join(New("A"), WithStack(New("B"))))
+
+	if !reflect.DeepEqual(val.Metadata, map[string]string{
+		"service": "checkout-service",
+		"version": "v1.4.2",
+	}) {
+		t.Error("bad metadata on the root value:", val.Metadata)
+	}
+
+	for _, cause := range val.Causes {
+		if cause.Metadata != nil {
+			t.Error("unexpected metadata on a child value:", cause.Metadata)
+		}
+	}
+
+	if val := ValueOf(nil); !val.IsNil() {
+		t.Error("the value of a nil error must be nil:", val)
+	}
+
+	SetValueMetadata(nil)
+
+	if val := ValueOf(New("A")); val.Metadata != nil {
+		t.Error("unexpected metadata after disabling it:", val.Metadata)
+	}
+}
+
+func TestValueStackPathRedactor(t *testing.T) {
+	defer SetValueStackPathRedactor(nil)
+	SetValueStackPathRedactor(func(file string) string {
+		return file[strings.LastIndexByte(file, '/')+1:]
+	})
+
+	val := ValueOf(New("hello world!"))
+
+	if len(val.Stack) == 0 || val.Stack[0] != "value_test.go:198:TestValueStackPathRedactor" {
+		t.Error("bad redacted stack:", val.Stack)
+	}
+
+	SetValueStackPathRedactor(nil)
+
+	val = ValueOf(New("hello world!"))
+
+	if len(val.Stack) == 0 || val.Stack[0] != "github.com/segmentio/errors-go/value_test.go:206:TestValueStackPathRedactor" {
+		t.Error("bad stack:", val.Stack)
+	}
+}
+
+func TestValueOfLimited(t *testing.T) {
+	t.Run("wide", func(t *testing.T) {
+		errs := make([]error, 100)
+		for i := range errs {
+			errs[i] = NewNoStack(fmt.Sprint(i))
+		}
+
+		val := ValueOfLimited(Join(errs...), ValueOptions{MaxCauses: 10})
+
+		if len(val.Causes) != 10 {
+			t.Error("bad number of causes:", len(val.Causes))
+		}
+
+		if tag := val.Tags[ValueTruncatedTag]; tag != "causes=90" {
+			t.Error("bad truncation tag:", tag)
+		}
+
+		if val.Causes[9].Message != "9" {
+			t.Error("bad message of the last cause:", val.Causes[9].Message)
+		}
+	})
+
+	t.Run("deep", func(t *testing.T) {
+		err := NewNoStack("leaf")
+		for i := 0; i != 100; i++ {
+			err = Join(err, NewNoStack(fmt.Sprint(i)))
+		}
+
+		val := ValueOfLimited(err, ValueOptions{MaxDepth: 3})
+		depth := 0
+
+		for len(val.Causes) != 0 {
+			val = val.Causes[0]
+			depth++
+		}
+
+		if depth != 3 {
+			t.Error("bad depth:", depth)
+		}
+
+		if tag := val.Tags[ValueTruncatedTag]; tag != "depth=2" {
+			t.Error("bad truncation tag:", tag)
+		}
+	})
+
+	t.Run("stack", func(t *testing.T) {
+		err := New("oops")
+
+		val := ValueOfLimited(err, ValueOptions{MaxStackFrames: 1})
+
+		if len(val.Stack) != 1 || !strings.Contains(val.Stack[0], "TestValueOfLimited") {
+			t.Error("bad stack:", val.Stack)
+		}
+
+		if n := len(ValueOf(err).Stack) - 1; val.Tags[ValueTruncatedTag] != fmt.Sprintf("frames=%d", n) {
+			t.Error("bad truncation tag:", val.Tags[ValueTruncatedTag])
+		}
+	})
+
+	t.Run("no limits", func(t *testing.T) {
+		err := Join(New("a"), New("b"))
+
+		if val := ValueOfLimited(err, ValueOptions{}); !val.Equal(ValueOf(err)) {
+			t.Error("values mismatch without limits:", val)
+		}
+	})
+}
+
+func TestValueEqual(t *testing.T) {
+	v1 := ValueOf(WithTags(Join(New("A"), New("B")), T("hello", "world")))
+	v2 := ValueOf(WithTags(Join(New("A"), New("B")), T("hello", "world")))
+
+	if !v1.Equal(v1) {
+		t.Error("a value must be equal to itself")
+	}
+
+	if v1.Equal(v2) {
+		t.Error("values with different stack traces must not be equal")
+	}
+
+	if !v1.Equal(v2, IgnoreStacks()) {
+		t.Error("values which only differ by their stack traces must be equal when ignoring stacks")
+	}
+
+	v3 := ValueOf(WithTags(Join(New("A"), New("C")), T("hello", "world")))
+
+	if v1.Equal(v3, IgnoreStacks()) {
+		t.Error("values with different causes must not be equal")
+	}
+
+	if !(Value{Tags: map[string]string{}}).Equal(Value{}) {
+		t.Error("nil and empty fields must be equal")
+	}
+}